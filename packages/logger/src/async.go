@@ -0,0 +1,173 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+)
+
+// OverflowPolicy decides what an AsyncHandler does when its buffer is full.
+type OverflowPolicy int
+
+const (
+	// OverflowBlock makes the logging call wait for buffer space. No records
+	// are lost. It is the default.
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDrop discards the record and counts it in Dropped.
+	OverflowDrop
+)
+
+// defaultAsyncBufferSize is used when AsyncOptions.BufferSize is not positive.
+const defaultAsyncBufferSize = 1024
+
+// AsyncOptions configures an AsyncHandler.
+type AsyncOptions struct {
+	// BufferSize is the number of records queued before Overflow applies.
+	BufferSize int
+	// Overflow selects blocking or dropping when the buffer is full.
+	Overflow OverflowPolicy
+}
+
+// AsyncHandler queues records on a channel and writes them to the wrapped
+// handler on a background goroutine, keeping slow writers off the hot path.
+// Call Close on shutdown to drain the queue.
+type AsyncHandler struct {
+	inner slog.Handler
+	queue *asyncQueue
+}
+
+// asyncQueue is shared by an AsyncHandler and every handler derived from it
+// with WithAttrs or WithGroup, so they drain through one goroutine in order.
+type asyncQueue struct {
+	entries  chan asyncEntry
+	overflow OverflowPolicy
+	dropped  atomic.Uint64
+
+	mu     sync.RWMutex // guards closed against concurrent sends
+	closed bool
+	done   chan struct{}
+}
+
+// asyncEntry is either a record for handler, or a flush marker when flushed
+// is non-nil.
+type asyncEntry struct {
+	ctx     context.Context
+	handler slog.Handler
+	record  slog.Record
+	flushed chan struct{}
+}
+
+// NewAsyncHandler starts a background writer for h.
+func NewAsyncHandler(h slog.Handler, opts AsyncOptions) *AsyncHandler {
+	size := opts.BufferSize
+	if size <= 0 {
+		size = defaultAsyncBufferSize
+	}
+
+	q := &asyncQueue{
+		entries:  make(chan asyncEntry, size),
+		overflow: opts.Overflow,
+		done:     make(chan struct{}),
+	}
+	go q.run()
+
+	return &AsyncHandler{inner: h, queue: q}
+}
+
+// WithAsync moves writes onto a background goroutine. Sync flushes it and
+// Close drains it.
+func WithAsync(opts AsyncOptions) Option {
+	return func(c *config) {
+		c.async = &opts
+	}
+}
+
+func (h *AsyncHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+// Handle enqueues a copy of r. After Close it writes synchronously so late
+// records are not lost.
+func (h *AsyncHandler) Handle(ctx context.Context, r slog.Record) error {
+	q := h.queue
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	if q.closed {
+		return h.inner.Handle(ctx, r)
+	}
+
+	// The record outlives this call, so it must be cloned, and the context
+	// must not be cancelled out from under the writer.
+	e := asyncEntry{ctx: context.WithoutCancel(ctx), handler: h.inner, record: r.Clone()}
+	if q.overflow == OverflowDrop {
+		select {
+		case q.entries <- e:
+		default:
+			q.dropped.Add(1)
+		}
+		return nil
+	}
+
+	q.entries <- e
+	return nil
+}
+
+func (h *AsyncHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &AsyncHandler{inner: h.inner.WithAttrs(attrs), queue: h.queue}
+}
+
+func (h *AsyncHandler) WithGroup(name string) slog.Handler {
+	return &AsyncHandler{inner: h.inner.WithGroup(name), queue: h.queue}
+}
+
+// Flush blocks until every record enqueued before the call has been written.
+func (h *AsyncHandler) Flush() error {
+	q := h.queue
+	q.mu.RLock()
+	if q.closed {
+		q.mu.RUnlock()
+		return nil
+	}
+
+	flushed := make(chan struct{})
+	q.entries <- asyncEntry{flushed: flushed}
+	q.mu.RUnlock()
+
+	<-flushed
+	return nil
+}
+
+// Close stops accepting records, drains the queue and waits for the writer to
+// exit. It is safe to call more than once.
+func (h *AsyncHandler) Close() error {
+	q := h.queue
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.entries)
+	}
+	q.mu.Unlock()
+
+	<-q.done
+	return nil
+}
+
+// Dropped returns how many records were discarded under OverflowDrop.
+func (h *AsyncHandler) Dropped() uint64 {
+	return h.queue.dropped.Load()
+}
+
+// run writes queued records until the channel is closed.
+func (q *asyncQueue) run() {
+	defer close(q.done)
+
+	for e := range q.entries {
+		if e.flushed != nil {
+			close(e.flushed)
+			continue
+		}
+		_ = e.handler.Handle(e.ctx, e.record)
+	}
+}
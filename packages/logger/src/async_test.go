@@ -0,0 +1,92 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+)
+
+func TestAsyncHandlerCloseDrains(t *testing.T) {
+	out := &syncBuffer{}
+	h := NewAsyncHandler(slog.NewJSONHandler(out, nil), AsyncOptions{BufferSize: 4})
+	l := slog.New(h).With("k", "v")
+
+	for range 100 {
+		l.Info("queued")
+	}
+	if err := h.Close(); err != nil {
+		t.Fatal(err)
+	}
+	l.Info("after close")
+
+	recs := records(t, out.String())
+	if len(recs) != 101 {
+		t.Fatalf("wrote %d records, want 101", len(recs))
+	}
+	if last := recs[100]; last[slog.MessageKey] != "after close" || last["k"] != "v" {
+		t.Errorf("last record = %v", last)
+	}
+}
+
+func TestAsyncHandlerFlush(t *testing.T) {
+	out := &syncBuffer{}
+	h := NewAsyncHandler(slog.NewJSONHandler(out, nil), AsyncOptions{})
+	t.Cleanup(func() { h.Close() })
+
+	slog.New(h).Info("one")
+	if err := h.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(records(t, out.String())); n != 1 {
+		t.Errorf("wrote %d records before Close, want 1", n)
+	}
+}
+
+// devNull opens a real file to write to, so that the benchmarks pay for a
+// write syscall per record as logging to stdout does.
+func devNull(b *testing.B) *os.File {
+	b.Helper()
+
+	f, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(func() { f.Close() })
+
+	return f
+}
+
+func benchmarkHandler(b *testing.B, h slog.Handler) {
+	l := slog.New(h).With("service", "bench")
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			l.InfoContext(ctx, "request handled", "status", 200, "path", "/api/v1/users")
+		}
+	})
+}
+
+func BenchmarkHandlerSync(b *testing.B) {
+	benchmarkHandler(b, slog.NewJSONHandler(devNull(b), nil))
+}
+
+func BenchmarkHandlerAsync(b *testing.B) {
+	for _, bc := range []struct {
+		name     string
+		overflow OverflowPolicy
+	}{
+		{"Block", OverflowBlock},
+		{"Drop", OverflowDrop},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			h := NewAsyncHandler(slog.NewJSONHandler(devNull(b), nil), AsyncOptions{Overflow: bc.overflow})
+			benchmarkHandler(b, h)
+			b.StopTimer()
+			h.Close()
+		})
+	}
+}
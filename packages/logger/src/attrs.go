@@ -0,0 +1,46 @@
+package logger
+
+import (
+	"log/slog"
+	"strconv"
+	"time"
+)
+
+// DurationUnit is the unit Duration and Since report in. Set it once at
+// startup; it defaults to milliseconds.
+var DurationUnit = time.Millisecond
+
+// ID returns an attribute holding v as a decimal string, for 64-bit IDs such
+// as snowflakes that JavaScript and other float64-based JSON consumers would
+// round past 2^53.
+//
+// This package never converts integers to floats: int, uint and their sized
+// variants passed to the helpers, With or slog.Int64 are written as exact
+// integers by every format. Precision is lost only downstream, or when a
+// value already went through float64, such as a number decoded from JSON
+// into an any.
+func ID(key string, v int64) slog.Attr {
+	return slog.String(key, strconv.FormatInt(v, 10))
+}
+
+// UID is ID for unsigned IDs.
+func UID(key string, v uint64) slog.Attr {
+	return slog.String(key, strconv.FormatUint(v, 10))
+}
+
+// Duration returns an attribute holding d as a number of DurationUnit, with
+// a fractional part, e.g. 12.5 for 12.5ms. Unlike slog.Duration, whose JSON
+// form is integer nanoseconds, it gives dashboards one consistent scale.
+func Duration(key string, d time.Duration) slog.Attr {
+	unit := DurationUnit
+	if unit <= 0 {
+		unit = time.Millisecond
+	}
+
+	return slog.Float64(key, float64(d)/float64(unit))
+}
+
+// Since is Duration for the time elapsed since start.
+func Since(key string, start time.Time) slog.Attr {
+	return Duration(key, time.Since(start))
+}
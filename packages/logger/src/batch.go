@@ -0,0 +1,302 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Defaults for BatchOptions fields left at zero.
+const (
+	defaultBatchSize     = 100
+	defaultBatchInterval = time.Second
+	defaultBatchQueue    = 10000
+	defaultBatchRetries  = 3
+	defaultBatchBackoff  = 200 * time.Millisecond
+	defaultBatchTimeout  = 10 * time.Second
+)
+
+// BatchOptions configures a BatchHandler. Zero values select the defaults.
+type BatchOptions struct {
+	// Client sends the requests; http.DefaultClient when nil.
+	Client *http.Client
+	// Header is added to every request, for example for authentication.
+	Header http.Header
+	// Level is the minimum level handled; Info when nil.
+	Level slog.Leveler
+	// BatchSize is the most records per request, 100 by default. A full
+	// batch is sent without waiting for FlushInterval.
+	BatchSize int
+	// FlushInterval is how often a partial batch is sent, 1s by default.
+	FlushInterval time.Duration
+	// QueueSize bounds the records held in memory, 10000 by default. When it
+	// is full the oldest record is dropped.
+	QueueSize int
+	// MaxRetries is how often a failed request is retried, 3 by default.
+	MaxRetries int
+	// Backoff is the wait before the first retry, doubling after each one;
+	// 200ms by default.
+	Backoff time.Duration
+	// Timeout bounds each request, including reading the response, so that a
+	// hung endpoint cannot stall Flush and Close; 10s by default. It applies
+	// on top of any timeout set on Client.
+	Timeout time.Duration
+}
+
+// BatchHandler POSTs records as newline-delimited JSON to an HTTP endpoint.
+// Records are queued in memory and sent on a background goroutine, so
+// logging never waits on the network. Requests that fail with a network
+// error, a 429 or a 5xx are retried with exponential backoff; batches that
+// still fail are dropped and reported through SetErrorReporter. Call Close
+// on shutdown to send the final batch.
+type BatchHandler struct {
+	json slog.Handler
+	sink *batchSink
+}
+
+// batchSink is shared by a BatchHandler and its WithAttrs/WithGroup children.
+// The JSON handler renders into buf, which Handle moves onto the queue.
+type batchSink struct {
+	url  string
+	opts BatchOptions
+
+	renderMu sync.Mutex
+	buf      bytes.Buffer
+
+	mu      sync.Mutex
+	queue   [][]byte
+	closed  bool
+	dropped atomic.Uint64
+
+	wake    chan struct{}
+	flushes chan chan struct{}
+	stop    chan struct{}
+	done    chan struct{}
+	once    sync.Once
+}
+
+func (s *batchSink) Write(p []byte) (int, error) {
+	return s.buf.Write(p)
+}
+
+// NewBatchHandler starts a background shipper posting to url. A nil opts
+// uses the defaults.
+func NewBatchHandler(url string, opts *BatchOptions) *BatchHandler {
+	var o BatchOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.Client == nil {
+		o.Client = http.DefaultClient
+	}
+	if o.BatchSize <= 0 {
+		o.BatchSize = defaultBatchSize
+	}
+	if o.FlushInterval <= 0 {
+		o.FlushInterval = defaultBatchInterval
+	}
+	if o.QueueSize <= 0 {
+		o.QueueSize = defaultBatchQueue
+	}
+	if o.MaxRetries <= 0 {
+		o.MaxRetries = defaultBatchRetries
+	}
+	if o.Backoff <= 0 {
+		o.Backoff = defaultBatchBackoff
+	}
+	if o.Timeout <= 0 {
+		o.Timeout = defaultBatchTimeout
+	}
+
+	s := &batchSink{
+		url:     url,
+		opts:    o,
+		wake:    make(chan struct{}, 1),
+		flushes: make(chan chan struct{}),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go s.run()
+
+	json := slog.NewJSONHandler(s, &slog.HandlerOptions{Level: o.Level, ReplaceAttr: replaceLevel})
+
+	return &BatchHandler{json: json, sink: s}
+}
+
+func (h *BatchHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.json.Enabled(ctx, level)
+}
+
+// Handle renders r and queues it. After Close the record is dropped, counted
+// in Dropped and reported, so that a late call never waits on the network.
+func (h *BatchHandler) Handle(ctx context.Context, r slog.Record) error {
+	s := h.sink
+	s.renderMu.Lock()
+	s.buf.Reset()
+	err := h.json.Handle(ctx, r)
+	line := bytes.Clone(s.buf.Bytes())
+	s.renderMu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		s.dropped.Add(1)
+		reportError(fmt.Errorf("logger: dropped a record logged after closing the batch handler for %s", s.url))
+		return nil
+	}
+	if len(s.queue) >= s.opts.QueueSize {
+		s.queue = s.queue[1:]
+		s.dropped.Add(1)
+	}
+	s.queue = append(s.queue, line)
+	full := len(s.queue) >= s.opts.BatchSize
+	s.mu.Unlock()
+
+	if full {
+		select {
+		case s.wake <- struct{}{}:
+		default:
+		}
+	}
+
+	return nil
+}
+
+func (h *BatchHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &BatchHandler{json: h.json.WithAttrs(attrs), sink: h.sink}
+}
+
+func (h *BatchHandler) WithGroup(name string) slog.Handler {
+	return &BatchHandler{json: h.json.WithGroup(name), sink: h.sink}
+}
+
+// Flush blocks until every record queued before the call has been sent or
+// given up on.
+func (h *BatchHandler) Flush() error {
+	f := make(chan struct{})
+	select {
+	case h.sink.flushes <- f:
+		<-f
+	case <-h.sink.done:
+	}
+
+	return nil
+}
+
+// Close sends the queued records and stops the shipper. It is safe to call
+// more than once.
+func (h *BatchHandler) Close() error {
+	s := h.sink
+	s.once.Do(func() { close(s.stop) })
+	<-s.done
+
+	return nil
+}
+
+// Dropped returns how many records were discarded because the queue was full
+// or the handler was closed.
+func (h *BatchHandler) Dropped() uint64 {
+	return h.sink.dropped.Load()
+}
+
+// run ships batches on every tick, full batch and flush request until Close.
+func (s *batchSink) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.opts.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.ship()
+		case <-s.wake:
+			s.ship()
+		case f := <-s.flushes:
+			s.ship()
+			close(f)
+		case <-s.stop:
+			s.mu.Lock()
+			s.closed = true
+			s.mu.Unlock()
+			s.ship()
+			return
+		}
+	}
+}
+
+// ship sends the queue in batches until it is empty.
+func (s *batchSink) ship() {
+	for {
+		s.mu.Lock()
+		n := min(len(s.queue), s.opts.BatchSize)
+		batch := s.queue[:n:n]
+		s.queue = s.queue[n:]
+		s.mu.Unlock()
+
+		if n == 0 {
+			return
+		}
+		if err := s.post(batch); err != nil {
+			reportError(err)
+		}
+	}
+}
+
+// post sends one batch, retrying transient failures.
+func (s *batchSink) post(batch [][]byte) error {
+	body := bytes.Join(batch, nil)
+
+	var err error
+	backoff := s.opts.Backoff
+	for attempt := 0; ; attempt++ {
+		var retry bool
+		retry, err = s.send(body)
+		if err == nil || !retry || attempt == s.opts.MaxRetries {
+			break
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+	if err != nil {
+		return fmt.Errorf("logger: shipping %d records to %s: %w", len(batch), s.url, err)
+	}
+
+	return nil
+}
+
+// send makes one request and reports whether a failure is worth retrying.
+func (s *batchSink) send(body []byte) (retry bool, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.opts.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	for k, v := range s.opts.Header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+
+	resp, err := s.opts.Client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		retry = resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		return retry, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	return false, nil
+}
@@ -0,0 +1,208 @@
+package logger
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// ingest is a test endpoint answering with the queued statuses, then 200,
+// and recording the bodies it receives.
+type ingest struct {
+	mu       sync.Mutex
+	statuses []int
+	bodies   []string
+}
+
+func (in *ingest) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	in.bodies = append(in.bodies, string(body))
+	if len(in.statuses) > 0 {
+		w.WriteHeader(in.statuses[0])
+		in.statuses = in.statuses[1:]
+	}
+}
+
+func (in *ingest) received() []string {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+
+	return append([]string(nil), in.bodies...)
+}
+
+func newIngest(t *testing.T, statuses ...int) (*ingest, string) {
+	t.Helper()
+
+	in := &ingest{statuses: statuses}
+	srv := httptest.NewServer(in)
+	t.Cleanup(srv.Close)
+
+	return in, srv.URL
+}
+
+func TestBatchHandlerRetries(t *testing.T) {
+	in, url := newIngest(t, http.StatusServiceUnavailable, http.StatusTooManyRequests)
+	h := NewBatchHandler(url, &BatchOptions{Backoff: time.Millisecond})
+	t.Cleanup(func() { h.Close() })
+
+	slog.New(h).Info("shipped", "n", 1)
+	h.Flush()
+
+	bodies := in.received()
+	if len(bodies) != 3 {
+		t.Fatalf("got %d requests, want 2 failures and a success", len(bodies))
+	}
+	for _, b := range bodies {
+		if !strings.Contains(b, `"msg":"shipped"`) {
+			t.Errorf("body %q misses the record", b)
+		}
+	}
+}
+
+func TestBatchHandlerGivesUp(t *testing.T) {
+	resetReporter(t)
+	report, got := collect()
+	SetErrorReporter(report)
+	t.Cleanup(func() { SetErrorReporter(nil) })
+
+	tests := []struct {
+		name     string
+		statuses []int
+		requests int
+	}{
+		{"not retried", []int{http.StatusBadRequest}, 1},
+		{"retries exhausted", []int{500, 500, 500}, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := len(got())
+			in, url := newIngest(t, tt.statuses...)
+			h := NewBatchHandler(url, &BatchOptions{MaxRetries: 2, Backoff: time.Millisecond})
+			t.Cleanup(func() { h.Close() })
+
+			slog.New(h).Info("lost")
+			h.Flush()
+
+			if n := len(in.received()); n != tt.requests {
+				t.Errorf("got %d requests, want %d", n, tt.requests)
+			}
+			if n := len(got()) - before; n != 1 {
+				t.Errorf("reported %d errors, want 1", n)
+			}
+		})
+	}
+}
+
+func TestBatchHandlerDropsOldest(t *testing.T) {
+	in, url := newIngest(t)
+	h := NewBatchHandler(url, &BatchOptions{QueueSize: 3, BatchSize: 100, FlushInterval: time.Hour})
+	t.Cleanup(func() { h.Close() })
+
+	l := slog.New(h)
+	for _, msg := range []string{"r1", "r2", "r3", "r4", "r5"} {
+		l.Info(msg)
+	}
+	if n := h.Dropped(); n != 2 {
+		t.Errorf("Dropped = %d, want 2", n)
+	}
+	h.Flush()
+
+	bodies := in.received()
+	if len(bodies) != 1 {
+		t.Fatalf("got %d requests, want 1", len(bodies))
+	}
+	var msgs []string
+	for _, r := range records(t, bodies[0]) {
+		msgs = append(msgs, r[slog.MessageKey].(string))
+	}
+	if got := strings.Join(msgs, ","); got != "r3,r4,r5" {
+		t.Errorf("shipped %s, want the newest three", got)
+	}
+}
+
+func TestBatchHandlerTimeout(t *testing.T) {
+	resetReporter(t)
+	report, got := collect()
+	SetErrorReporter(report)
+	t.Cleanup(func() { SetErrorReporter(nil) })
+
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	t.Cleanup(srv.Close)
+	t.Cleanup(func() { close(release) })
+
+	h := NewBatchHandler(srv.URL, &BatchOptions{Timeout: 20 * time.Millisecond, MaxRetries: 1, Backoff: time.Millisecond})
+	slog.New(h).Info("stuck")
+
+	done := make(chan struct{})
+	go func() {
+		h.Close()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Close hung on an unresponsive endpoint")
+	}
+
+	if errs := got(); len(errs) != 1 {
+		t.Errorf("reported %v, want the timeout", errs)
+	}
+}
+
+func TestBatchHandlerDropsAfterClose(t *testing.T) {
+	resetReporter(t)
+	report, got := collect()
+	SetErrorReporter(report)
+	t.Cleanup(func() { SetErrorReporter(nil) })
+
+	in, url := newIngest(t)
+	h := NewBatchHandler(url, nil)
+	h.Close()
+
+	slog.New(h).Info("late")
+
+	if n := h.Dropped(); n != 1 {
+		t.Errorf("Dropped = %d, want 1", n)
+	}
+	if n := len(in.received()); n != 0 {
+		t.Errorf("sent %d requests after Close", n)
+	}
+	if n := len(got()); n != 1 {
+		t.Errorf("reported %d errors, want 1", n)
+	}
+}
+
+func TestBatchHandlerInWithHandlers(t *testing.T) {
+	in, url := newIngest(t)
+	h := NewBatchHandler(url, &BatchOptions{FlushInterval: time.Hour})
+
+	l, res := Open(WithWriter(&syncBuffer{}), WithHandlers(h))
+	l.Info("synced")
+	if err := res.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(in.received()); n != 1 {
+		t.Fatalf("Sync sent %d requests, want 1", n)
+	}
+
+	l.Info("closed")
+	if err := res.Close(); err != nil {
+		t.Fatal(err)
+	}
+	bodies := in.received()
+	if len(bodies) != 2 || !strings.Contains(bodies[1], `"msg":"closed"`) {
+		t.Errorf("Close did not send the final batch: %q", bodies)
+	}
+}
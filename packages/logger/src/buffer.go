@@ -0,0 +1,137 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"sync"
+)
+
+const bufferKey contextKey = "buffer"
+
+// maxBufferedRecords bounds a Buffer; the oldest records are dropped beyond it.
+const maxBufferedRecords = 1024
+
+// Buffer holds the records logged through Ctx for a context returned by
+// WithBuffer until the caller decides their fate, typically once a request
+// has finished: Flush writes them, for a failed request, and Discard drops
+// them, for a successful one. Either way, later records are written directly.
+// It keeps at most 1024 records, dropping the oldest.
+//
+// The logger's level still applies, so to capture debug records combine it
+// with ContextWithLevel:
+//
+//	ctx, buf := logger.WithBuffer(logger.ContextWithLevel(ctx, slog.LevelDebug))
+//	defer func() {
+//		if err != nil {
+//			buf.Flush()
+//		} else {
+//			buf.Discard()
+//		}
+//	}()
+type Buffer struct {
+	mu      sync.Mutex
+	entries []asyncEntry
+	done    bool
+	flushOn slog.Level
+}
+
+// WithBuffer returns a context whose records are held in the returned Buffer.
+func WithBuffer(ctx context.Context) (context.Context, *Buffer) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	b := &Buffer{flushOn: slog.Level(math.MaxInt)}
+	return context.WithValue(ctx, bufferKey, b), b
+}
+
+// FlushOn makes a record at level or above flush the buffer, itself included,
+// so errors are written as they happen along with what led up to them.
+func (b *Buffer) FlushOn(level slog.Level) *Buffer {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.flushOn = level
+	return b
+}
+
+// Flush writes the held records in order, and every later one directly.
+func (b *Buffer) Flush() error {
+	b.mu.Lock()
+	entries := b.entries
+	b.entries, b.done = nil, true
+	b.mu.Unlock()
+
+	for _, e := range entries {
+		_ = e.handler.Handle(e.ctx, e.record)
+	}
+
+	return nil
+}
+
+// Discard drops the held records; later ones are written directly.
+func (b *Buffer) Discard() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.entries, b.done = nil, true
+}
+
+// hold queues r for h, reporting false when the record should be written
+// now instead.
+func (b *Buffer) hold(ctx context.Context, h slog.Handler, r slog.Record) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.done || r.Level >= b.flushOn {
+		return false
+	}
+	if len(b.entries) >= maxBufferedRecords {
+		b.entries = b.entries[1:]
+	}
+	b.entries = append(b.entries, asyncEntry{
+		ctx:     context.WithoutCancel(ctx),
+		handler: h,
+		record:  r.Clone(),
+	})
+
+	return true
+}
+
+// bufferFromContext returns the Buffer stored by WithBuffer.
+func bufferFromContext(ctx context.Context) *Buffer {
+	b, _ := ctx.Value(bufferKey).(*Buffer)
+	return b
+}
+
+// bufferHandler diverts records into a Buffer.
+type bufferHandler struct {
+	inner slog.Handler
+	buf   *Buffer
+}
+
+func (h *bufferHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+func (h *bufferHandler) Handle(ctx context.Context, r slog.Record) error {
+	if h.buf.hold(ctx, h.inner, r) {
+		return nil
+	}
+
+	// A record that triggers FlushOn follows what was held before it
+	if err := h.buf.Flush(); err != nil {
+		return err
+	}
+
+	return h.inner.Handle(ctx, r)
+}
+
+func (h *bufferHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &bufferHandler{inner: h.inner.WithAttrs(attrs), buf: h.buf}
+}
+
+func (h *bufferHandler) WithGroup(name string) slog.Handler {
+	return &bufferHandler{inner: h.inner.WithGroup(name), buf: h.buf}
+}
@@ -0,0 +1,47 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// WithClock replaces the clock of a logger built with New, for tests that
+// assert exact timestamps or exercise time windows. It sets the time of every
+// record and drives the windows of WithSampling, WithDedup and the rate
+// limits, along with the time of the summary records they emit. Windows end
+// once the clock has passed them, as seen by the next record or a Flush, not
+// on a wall-clock timer, so tests advance the clock instead of sleeping.
+// Production code should leave the default, time.Now.
+func WithClock(now func() time.Time) Option {
+	return func(c *config) {
+		c.now = now
+	}
+}
+
+// clockHandler stamps records with the configured clock.
+type clockHandler struct {
+	inner slog.Handler
+	now   func() time.Time
+}
+
+func (h *clockHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+func (h *clockHandler) Handle(ctx context.Context, r slog.Record) error {
+	// A zero time means the caller wants no time attribute
+	if !r.Time.IsZero() {
+		r.Time = h.now()
+	}
+
+	return h.inner.Handle(ctx, r)
+}
+
+func (h *clockHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &clockHandler{inner: h.inner.WithAttrs(attrs), now: h.now}
+}
+
+func (h *clockHandler) WithGroup(name string) slog.Handler {
+	return &clockHandler{inner: h.inner.WithGroup(name), now: h.now}
+}
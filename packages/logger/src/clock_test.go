@@ -0,0 +1,41 @@
+package logger
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// testClock is a WithClock clock that only moves when advanced.
+type testClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newTestClock() *testClock {
+	return &testClock{now: time.Unix(1_700_000_000, 0).UTC()}
+}
+
+func (c *testClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *testClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+func TestClockStampsRecords(t *testing.T) {
+	out := &syncBuffer{}
+	clock := newTestClock()
+	New(WithWriter(out), WithClock(clock.Now)).Info("hi")
+
+	if got, want := records(t, out.String())[0]["time"], clock.Now().Format(time.RFC3339); got != want {
+		t.Errorf("time = %v, want %v", got, want)
+	}
+}
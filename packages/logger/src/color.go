@@ -0,0 +1,163 @@
+package logger
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+)
+
+// ColorMode selects whether FormatText output is colored.
+type ColorMode int
+
+const (
+	// ColorAuto colors output written to a terminal, unless NO_COLOR is set.
+	// It is the default.
+	ColorAuto ColorMode = iota
+	// ColorAlways colors output wherever it goes.
+	ColorAlways
+	// ColorNever disables colors.
+	ColorNever
+)
+
+// ANSI escape sequences used for colored output.
+const (
+	ansiReset   = "\x1b[0m"
+	ansiDim     = "\x1b[2m"
+	ansiRed     = "\x1b[31m"
+	ansiGreen   = "\x1b[32m"
+	ansiYellow  = "\x1b[33m"
+	ansiCyan    = "\x1b[36m"
+	ansiMagenta = "\x1b[35m"
+)
+
+// WithColor sets when FormatText colors the level and dims the time. Other
+// formats are never colored.
+func WithColor(mode ColorMode) Option {
+	return func(c *config) {
+		c.color = mode
+	}
+}
+
+// colorize wraps w for colored text output if the config's mode asks for it.
+func (c *config) colorize(w io.Writer) io.Writer {
+	switch c.color {
+	case ColorNever:
+		return w
+	case ColorAuto:
+		if _, ok := os.LookupEnv("NO_COLOR"); ok || !isTerminal(w) {
+			return w
+		}
+	}
+
+	timeKey := slog.TimeKey
+	if c.time.key != "" {
+		timeKey = c.time.key
+	}
+	if c.time.omit {
+		timeKey = ""
+	}
+
+	levelKey := slog.LevelKey
+	if c.levelKey != "" {
+		levelKey = c.levelKey
+	}
+
+	return &colorWriter{w: w, timeKey: timeKey, levelKey: levelKey}
+}
+
+// isTerminal reports whether w is a character device such as a TTY.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// colorWriter colors the leading time and level tokens of each text line.
+// slog's text handler writes one whole record per Write call.
+type colorWriter struct {
+	w        io.Writer
+	timeKey  string
+	levelKey string
+}
+
+func (cw *colorWriter) Write(p []byte) (int, error) {
+	out := make([]byte, 0, len(p)+32)
+	rest := p
+
+	if cw.timeKey != "" {
+		if tok, ok := leadingToken(rest, cw.timeKey); ok {
+			out = append(out, ansiDim...)
+			out = append(out, tok...)
+			out = append(out, ansiReset...)
+			rest = rest[len(tok):]
+			if len(rest) > 0 && rest[0] == ' ' {
+				out = append(out, ' ')
+				rest = rest[1:]
+			}
+		}
+	}
+
+	if tok, ok := leadingToken(rest, cw.levelKey); ok {
+		value := tok[len(cw.levelKey)+1:]
+		out = append(out, cw.levelKey...)
+		out = append(out, '=')
+		out = append(out, levelColor(value)...)
+		out = append(out, value...)
+		out = append(out, ansiReset...)
+		rest = rest[len(tok):]
+	}
+
+	out = append(out, rest...)
+	if _, err := cw.w.Write(out); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// leadingToken returns the key=value token at the start of p, allowing for a
+// quoted value.
+func leadingToken(p []byte, key string) ([]byte, bool) {
+	if !bytes.HasPrefix(p, []byte(key+"=")) {
+		return nil, false
+	}
+
+	end := len(key) + 1
+	if end < len(p) && p[end] == '"' {
+		q, err := strconv.QuotedPrefix(string(p[end:]))
+		if err != nil {
+			return nil, false
+		}
+		return p[:end+len(q)], true
+	}
+	if i := bytes.IndexAny(p[end:], " \n"); i >= 0 {
+		end += i
+	} else {
+		end = len(p)
+	}
+
+	return p[:end], true
+}
+
+// levelColor picks the color for a rendered level such as "WARN" or
+// "ERROR+2".
+func levelColor(level []byte) string {
+	switch {
+	case bytes.HasPrefix(level, []byte("ERROR")):
+		return ansiRed
+	case bytes.HasPrefix(level, []byte("WARN")):
+		return ansiYellow
+	case bytes.HasPrefix(level, []byte("INFO")):
+		return ansiGreen
+	case bytes.HasPrefix(level, []byte("DEBUG")):
+		return ansiCyan
+	default:
+		return ansiMagenta
+	}
+}
@@ -0,0 +1,65 @@
+package logger
+
+import (
+	"errors"
+	"log/slog"
+	"sync/atomic"
+)
+
+// ErrConfigured is returned by Configure when the default logger was
+// already configured.
+var ErrConfigured = errors.New("logger: already configured")
+
+// configured is set by the first successful Configure.
+var configured atomic.Bool
+
+// Config describes the default logger built by Configure.
+type Config struct {
+	// Level and Format take the values accepted by LOG_LEVEL and LOG_FORMAT
+	// and override them. Empty keeps the environment's choice.
+	Level  string
+	Format string
+
+	// Options are applied last, as for New.
+	Options []Option
+}
+
+// Configure builds the default logger from cfg and the LOG_* environment
+// and swaps it in atomically, along with slog's default. Call it once, early
+// in main; later calls return ErrConfigured, and an invalid Level or Format
+// returns an error and leaves the default untouched.
+//
+// Package init functions run before main, so records they log go to the
+// logger built from the environment on first use, and records logged
+// concurrently with Configure go to one logger or the other. Loggers derived
+// before the swap, e.g. by Named in a package-level var, keep the old one.
+// Unless Options set a level, SetLevel keeps working.
+func Configure(cfg Config) error {
+	opts := append([]Option{WithLevel(level)}, envOpts...)
+
+	var lvl *slog.Level
+	if cfg.Level != "" {
+		l, err := ParseLevel(cfg.Level)
+		if err != nil {
+			return err
+		}
+		lvl = &l
+	}
+	if cfg.Format != "" {
+		f, err := ParseFormat(cfg.Format)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, WithFormat(f))
+	}
+
+	if !configured.CompareAndSwap(false, true) {
+		return ErrConfigured
+	}
+	if lvl != nil {
+		level.Set(*lvl)
+	}
+	SetDefault(New(append(opts, cfg.Options...)...))
+
+	return nil
+}
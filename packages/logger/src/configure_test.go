@@ -0,0 +1,96 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+)
+
+// resetDefault puts the package back in its state after init, before the
+// default logger is built, and restores the current default afterwards.
+func resetDefault(t *testing.T) {
+	t.Helper()
+
+	prev, prevSlog, prevLevel := defaultLogger.Load(), slog.Default(), level.Level()
+	defaultLogger.Store(nil)
+	slog.SetDefault(slog.New(lazyHandler{}))
+	configured.Store(false)
+
+	t.Cleanup(func() {
+		defaultLogger.Store(prev)
+		slog.SetDefault(prevSlog)
+		configured.Store(false)
+		level.Set(prevLevel)
+	})
+}
+
+func TestConfigureWhileLogging(t *testing.T) {
+	resetDefault(t)
+
+	ctx := WithCorrelation(context.Background(), "abc")
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			for range 200 {
+				Ctx(ctx).Debug("tick")
+				slog.Debug("tock")
+			}
+		}()
+	}
+
+	close(start)
+	err := Configure(Config{Level: "warn", Options: []Option{WithWriter(io.Discard)}})
+	wg.Wait()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if GetLevel() != slog.LevelWarn {
+		t.Errorf("level = %v, want WARN", GetLevel())
+	}
+	if slog.Default() != Default() {
+		t.Error("slog's default was not swapped")
+	}
+}
+
+func TestConfigureOnce(t *testing.T) {
+	resetDefault(t)
+
+	if err := Configure(Config{Format: "yaml"}); err == nil {
+		t.Fatal("invalid format accepted")
+	}
+	if defaultLogger.Load() != nil {
+		t.Error("failed Configure built a logger")
+	}
+
+	if err := Configure(Config{Options: []Option{WithWriter(io.Discard)}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Configure(Config{}); !errors.Is(err, ErrConfigured) {
+		t.Errorf("second Configure = %v, want ErrConfigured", err)
+	}
+}
+
+func TestSetDefaultWithSlogDefault(t *testing.T) {
+	for name, set := range map[string]func(){
+		"SetDefault": func() { SetDefault(slog.Default()) },
+		"SetHandler": func() { SetHandler(slog.Default().Handler()) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			resetDefault(t)
+
+			set()
+			if _, ok := Default().Handler().(lazyHandler); ok {
+				t.Fatal("stored slog's placeholder as the default")
+			}
+			Default().Enabled(context.Background(), slog.LevelInfo)
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+)
+
+// ContextErrorKey is the attribute added by WithContextErrorAttr.
+const ContextErrorKey = "ctx_err"
+
+// WithContextErrorAttr adds a ctx_err attribute to records logged with a
+// context that is already cancelled or past its deadline, to expose work that
+// continues after its request ended.
+func WithContextErrorAttr() Option {
+	return func(c *config) {
+		c.ctxErr = true
+	}
+}
+
+// ctxErrHandler annotates records whose context is done.
+type ctxErrHandler struct {
+	inner slog.Handler
+}
+
+func (h *ctxErrHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+func (h *ctxErrHandler) Handle(ctx context.Context, r slog.Record) error {
+	if ctx != nil {
+		if err := ctx.Err(); err != nil {
+			r = r.Clone()
+			r.AddAttrs(slog.String(ContextErrorKey, err.Error()))
+		}
+	}
+
+	return h.inner.Handle(ctx, r)
+}
+
+func (h *ctxErrHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &ctxErrHandler{inner: h.inner.WithAttrs(attrs)}
+}
+
+func (h *ctxErrHandler) WithGroup(name string) slog.Handler {
+	return &ctxErrHandler{inner: h.inner.WithGroup(name)}
+}
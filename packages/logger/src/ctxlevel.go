@@ -0,0 +1,64 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"math"
+)
+
+const levelKey contextKey = "level"
+
+// allLevels lets output handlers accept every record; the effective threshold
+// is enforced by levelHandler so that it can be overridden per context.
+const allLevels = slog.Level(math.MinInt)
+
+// ContextWithLevel returns a context whose records are filtered at l instead
+// of the logger's configured level, e.g. to enable debug logging for a single
+// request. It can raise the threshold as well as lower it. The override is
+// seen by the package helpers and by slog methods that take the context, such
+// as Logger.DebugContext. Handlers attached with WithHandlers keep their own
+// levels.
+func ContextWithLevel(ctx context.Context, l slog.Level) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	return context.WithValue(ctx, levelKey, l)
+}
+
+// levelFromContext returns the override stored by ContextWithLevel.
+func levelFromContext(ctx context.Context) (slog.Level, bool) {
+	if ctx == nil {
+		return 0, false
+	}
+
+	l, ok := ctx.Value(levelKey).(slog.Level)
+	return l, ok
+}
+
+// levelHandler enforces the logger's level, or the context's override.
+type levelHandler struct {
+	inner slog.Handler
+	level slog.Leveler
+}
+
+func (h *levelHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	min, ok := levelFromContext(ctx)
+	if !ok {
+		min = h.level.Level()
+	}
+
+	return level >= min && h.inner.Enabled(ctx, level)
+}
+
+func (h *levelHandler) Handle(ctx context.Context, r slog.Record) error {
+	return h.inner.Handle(ctx, r)
+}
+
+func (h *levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &levelHandler{inner: h.inner.WithAttrs(attrs), level: h.level}
+}
+
+func (h *levelHandler) WithGroup(name string) slog.Handler {
+	return &levelHandler{inner: h.inner.WithGroup(name), level: h.level}
+}
@@ -0,0 +1,86 @@
+package logger
+
+import (
+	"context"
+	"encoding/binary"
+	"log/slog"
+	"strconv"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Attribute keys read by the Datadog agent to link logs to APM traces.
+const (
+	DatadogTraceIDKey = "dd.trace_id"
+	DatadogSpanIDKey  = "dd.span_id"
+)
+
+// WithDatadog makes Ctx add dd.trace_id and dd.span_id next to trace_id and
+// span_id when the context carries an OpenTelemetry span. As Datadog
+// documents for OpenTelemetry, its trace ID is the lower 64 bits of the
+// 128-bit trace ID, both IDs written as unsigned decimal strings.
+func WithDatadog() Option {
+	return func(c *config) {
+		c.datadog = true
+	}
+}
+
+// datadogHandler adds the Datadog attributes next to the span_id that Ctx
+// attaches. It finds them through any handler wrapping the logger, such as
+// AddHandlers or a metrics handler, since those pass WithAttrs on.
+type datadogHandler struct {
+	inner slog.Handler
+}
+
+func (h *datadogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+func (h *datadogHandler) Handle(ctx context.Context, r slog.Record) error {
+	return h.inner.Handle(ctx, r)
+}
+
+func (h *datadogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	for _, a := range attrs {
+		if v, ok := a.Value.Any().(spanIDValue); ok {
+			attrs = append(attrs[:len(attrs):len(attrs)], datadogAttrs(v.span)...)
+			break
+		}
+	}
+
+	return &datadogHandler{inner: h.inner.WithAttrs(attrs)}
+}
+
+func (h *datadogHandler) WithGroup(name string) slog.Handler {
+	return &datadogHandler{inner: h.inner.WithGroup(name)}
+}
+
+// spanIDValue is the span_id value set by Ctx. It logs as the hex span ID
+// and carries the span for datadogHandler.
+type spanIDValue struct {
+	span trace.SpanContext
+}
+
+func (v spanIDValue) LogValue() slog.Value {
+	return slog.StringValue(v.span.SpanID().String())
+}
+
+// datadogAttrs returns the Datadog attributes for span.
+func datadogAttrs(span trace.SpanContext) []slog.Attr {
+	attrs := []slog.Attr{slog.String(DatadogTraceIDKey, datadogTraceID(span.TraceID()))}
+	if span.HasSpanID() {
+		attrs = append(attrs, slog.String(DatadogSpanIDKey, datadogSpanID(span.SpanID())))
+	}
+
+	return attrs
+}
+
+// datadogTraceID converts a 128-bit trace ID to Datadog's 64-bit form.
+func datadogTraceID(id trace.TraceID) string {
+	return strconv.FormatUint(binary.BigEndian.Uint64(id[8:]), 10)
+}
+
+// datadogSpanID formats a span ID as Datadog expects.
+func datadogSpanID(id trace.SpanID) string {
+	return strconv.FormatUint(binary.BigEndian.Uint64(id[:]), 10)
+}
@@ -0,0 +1,63 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestDatadogThroughWrappers(t *testing.T) {
+	span := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{0: 1, 8: 0, 15: 42},
+		SpanID:  trace.SpanID{7: 7},
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), span)
+
+	tests := []struct {
+		name string
+		wrap func(slog.Handler) slog.Handler
+	}{
+		{"direct", func(h slog.Handler) slog.Handler { return h }},
+		{"multi", func(h slog.Handler) slog.Handler {
+			return NewMultiHandler(h, slog.NewJSONHandler(&syncBuffer{}, nil))
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &syncBuffer{}
+			l := slog.New(tt.wrap(New(WithWriter(out), WithDatadog()).Handler()))
+
+			Ctx(WithLogger(ctx, l)).Info("hi")
+
+			r := records(t, out.String())[0]
+			want := map[string]any{
+				"span_id":         span.SpanID().String(),
+				DatadogTraceIDKey: "42",
+				DatadogSpanIDKey:  "7",
+			}
+			for k, v := range want {
+				if r[k] != v {
+					t.Errorf("%s = %v, want %v", k, r[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestDatadogOnlyWithOption(t *testing.T) {
+	span := trace.NewSpanContext(trace.SpanContextConfig{TraceID: trace.TraceID{15: 1}, SpanID: trace.SpanID{7: 1}})
+	ctx := trace.ContextWithSpanContext(context.Background(), span)
+
+	out := &syncBuffer{}
+	Ctx(WithLogger(ctx, New(WithWriter(out)))).Info("hi")
+
+	r := records(t, out.String())[0]
+	if _, ok := r[DatadogTraceIDKey]; ok {
+		t.Errorf("dd attrs without WithDatadog: %v", r)
+	}
+	if r["span_id"] != span.SpanID().String() {
+		t.Errorf("span_id = %v", r["span_id"])
+	}
+}
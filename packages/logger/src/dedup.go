@@ -0,0 +1,224 @@
+package logger
+
+import (
+	"context"
+	"hash/maphash"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DedupCountKey is the attribute added to the summary record emitted when
+// duplicates were suppressed.
+const DedupCountKey = "count"
+
+// WithDedup collapses identical records seen within interval, see
+// NewDedupHandler.
+func WithDedup(interval time.Duration) Option {
+	return func(c *config) {
+		c.dedup = interval
+	}
+}
+
+// dedupHandler suppresses repeats of a record within a window and reports how
+// often it occurred once the window closes.
+type dedupHandler struct {
+	inner slog.Handler
+	seed  uint64 // identifies the attrs and groups of this derived handler
+	state *dedupState
+}
+
+// dedupState is shared by all handlers derived from one NewDedupHandler.
+type dedupState struct {
+	interval time.Duration
+	hashSeed maphash.Seed
+	now      func() time.Time
+
+	mu      sync.Mutex
+	pending map[uint64]*dedupEntry
+}
+
+// dedupEntry tracks one distinct record within its window.
+type dedupEntry struct {
+	ctx     context.Context
+	handler slog.Handler
+	record  slog.Record
+	count   int
+	start   time.Time
+	timer   *time.Timer
+}
+
+// NewDedupHandler wraps h so that a record identical to one seen less than
+// interval ago (same level, message and attributes) is not written. The first
+// occurrence is logged immediately; when the window closes, a copy carrying a
+// "count" attribute with the total number of occurrences is logged if there
+// were repeats. The returned handler implements Flusher to emit pending
+// summaries early, e.g. on shutdown.
+func NewDedupHandler(h slog.Handler, interval time.Duration) slog.Handler {
+	return newDedupHandler(h, interval, time.Now)
+}
+
+// newDedupHandler is NewDedupHandler with the clock of WithClock.
+func newDedupHandler(h slog.Handler, interval time.Duration, now func() time.Time) slog.Handler {
+	return &dedupHandler{
+		inner: h,
+		state: &dedupState{
+			interval: interval,
+			hashSeed: maphash.MakeSeed(),
+			now:      now,
+			pending:  make(map[uint64]*dedupEntry),
+		},
+	}
+}
+
+func (h *dedupHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+func (h *dedupHandler) Handle(ctx context.Context, r slog.Record) error {
+	s := h.state
+	key := h.hash(r)
+
+	now := s.now()
+	s.mu.Lock()
+	var expired *dedupEntry
+	if e, ok := s.pending[key]; ok {
+		// The timer may lag behind the clock; the window is over either way
+		if now.Sub(e.start) < s.interval {
+			e.count++
+			s.mu.Unlock()
+			return nil
+		}
+		e.timer.Stop()
+		delete(s.pending, key)
+		expired = e
+	}
+
+	e := &dedupEntry{
+		ctx:     context.WithoutCancel(ctx),
+		handler: h.inner,
+		record:  r.Clone(),
+		count:   1,
+		start:   now,
+	}
+	e.timer = time.AfterFunc(s.interval, func() { s.expire(key, e) })
+	s.pending[key] = e
+	s.mu.Unlock()
+
+	if expired != nil {
+		expired.summarize(now)
+	}
+
+	return h.inner.Handle(ctx, r)
+}
+
+func (h *dedupHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var mh maphash.Hash
+	mh.SetSeed(h.state.hashSeed)
+	writeUint(&mh, h.seed)
+	for _, a := range attrs {
+		writeAttr(&mh, a)
+	}
+
+	return &dedupHandler{inner: h.inner.WithAttrs(attrs), seed: mh.Sum64(), state: h.state}
+}
+
+func (h *dedupHandler) WithGroup(name string) slog.Handler {
+	var mh maphash.Hash
+	mh.SetSeed(h.state.hashSeed)
+	writeUint(&mh, h.seed)
+	mh.WriteString("(" + name)
+
+	return &dedupHandler{inner: h.inner.WithGroup(name), seed: mh.Sum64(), state: h.state}
+}
+
+// Flush emits the summaries of all open windows immediately.
+func (h *dedupHandler) Flush() error {
+	s := h.state
+	s.mu.Lock()
+	entries := make([]*dedupEntry, 0, len(s.pending))
+	for key, e := range s.pending {
+		e.timer.Stop()
+		delete(s.pending, key)
+		entries = append(entries, e)
+	}
+	s.mu.Unlock()
+
+	for _, e := range entries {
+		e.summarize(s.now())
+	}
+
+	return nil
+}
+
+// Close emits pending summaries; the handler stays usable.
+func (h *dedupHandler) Close() error {
+	return h.Flush()
+}
+
+// expire closes the window for key when its timer fires, unless Flush or a
+// later record already did. With WithClock the window stays open until the
+// clock has moved past it; the next matching record or Flush then closes it.
+func (s *dedupState) expire(key uint64, e *dedupEntry) {
+	now := s.now()
+	s.mu.Lock()
+	if s.pending[key] != e || now.Sub(e.start) < s.interval {
+		s.mu.Unlock()
+		return
+	}
+	delete(s.pending, key)
+	s.mu.Unlock()
+
+	e.summarize(now)
+}
+
+// summarize logs the record again at now with its occurrence count if it
+// repeated.
+func (e *dedupEntry) summarize(now time.Time) {
+	if e.count < 2 {
+		return
+	}
+
+	r := e.record.Clone()
+	r.Time = now
+	r.AddAttrs(slog.Int(DedupCountKey, e.count))
+	_ = e.handler.Handle(e.ctx, r)
+}
+
+// hash identifies r by level, message and attributes within this handler.
+func (h *dedupHandler) hash(r slog.Record) uint64 {
+	var mh maphash.Hash
+	mh.SetSeed(h.state.hashSeed)
+	writeUint(&mh, h.seed)
+	writeUint(&mh, uint64(r.Level))
+	mh.WriteString(r.Message)
+	r.Attrs(func(a slog.Attr) bool {
+		writeAttr(&mh, a)
+		return true
+	})
+
+	return mh.Sum64()
+}
+
+// writeAttr feeds a and any nested group attributes to mh.
+func writeAttr(mh *maphash.Hash, a slog.Attr) {
+	mh.WriteString(a.Key)
+	mh.WriteByte('=')
+	if a.Value.Kind() == slog.KindGroup {
+		mh.WriteByte('{')
+		for _, ga := range a.Value.Group() {
+			writeAttr(mh, ga)
+		}
+		mh.WriteByte('}')
+		return
+	}
+	mh.WriteString(a.Value.String())
+	mh.WriteByte(';')
+}
+
+// writeUint feeds v to mh as decimal text.
+func writeUint(mh *maphash.Hash, v uint64) {
+	var buf [20]byte
+	mh.Write(strconv.AppendUint(buf[:0], v, 10))
+}
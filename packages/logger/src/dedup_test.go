@@ -0,0 +1,129 @@
+package logger
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestDedupWindowFollowsClock(t *testing.T) {
+	l, out, clock := newTestDedup(t, 20*time.Millisecond)
+
+	l.Info("repeat")
+	// Past the wall-clock timer, with the clock still inside the window
+	time.Sleep(60 * time.Millisecond)
+	l.Info("repeat")
+
+	if n := len(records(t, out.String())); n != 1 {
+		t.Fatalf("wrote %d records, want the repeat suppressed", n)
+	}
+
+	clock.Advance(20 * time.Millisecond)
+	l.Info("repeat")
+
+	recs := records(t, out.String())
+	if len(recs) != 3 || recs[1][DedupCountKey] != float64(2) {
+		t.Errorf("got %v, want the summary and then a fresh record", recs)
+	}
+}
+
+// newTestDedup returns a dedup logger on a test clock writing to out.
+func newTestDedup(t *testing.T, window time.Duration) (*slog.Logger, *syncBuffer, *testClock) {
+	t.Helper()
+
+	out := &syncBuffer{}
+	clock := newTestClock()
+	l, res := Open(WithWriter(out), WithDedup(window), WithClock(clock.Now))
+	t.Cleanup(func() { res.Close() })
+
+	return l, out, clock
+}
+
+func TestDedupCollapsesRepeats(t *testing.T) {
+	l, out, clock := newTestDedup(t, time.Minute)
+
+	for range 3 {
+		l.Info("repeat", "k", 1)
+	}
+	l.Info("repeat", "k", 2)
+	l.Warn("repeat", "k", 1)
+
+	if n := len(records(t, out.String())); n != 3 {
+		t.Fatalf("wrote %d records, want one per distinct record", n)
+	}
+
+	clock.Advance(time.Minute)
+	l.Info("repeat", "k", 1)
+
+	recs := records(t, out.String())
+	if len(recs) != 5 {
+		t.Fatalf("got %v, want the summary and a fresh record", recs)
+	}
+	summary := recs[3]
+	if summary[DedupCountKey] != float64(3) || summary["k"] != float64(1) {
+		t.Errorf("summary = %v, want count 3 of k=1", summary)
+	}
+	if summary["time"] != clock.Now().Format(time.RFC3339Nano) {
+		t.Errorf("summary time = %v, want the closing time %v", summary["time"], clock.Now())
+	}
+	if _, ok := recs[4][DedupCountKey]; ok {
+		t.Errorf("fresh record carries a count: %v", recs[4])
+	}
+}
+
+func TestDedupNoSummaryWithoutRepeats(t *testing.T) {
+	l, out, clock := newTestDedup(t, time.Minute)
+
+	l.Info("once")
+	clock.Advance(time.Minute)
+	l.Info("once")
+
+	for _, r := range records(t, out.String()) {
+		if _, ok := r[DedupCountKey]; ok {
+			t.Errorf("summary for a record seen once: %v", r)
+		}
+	}
+}
+
+func TestDedupSeparatesWithAttrs(t *testing.T) {
+	l, out, _ := newTestDedup(t, time.Minute)
+
+	l.With("user", "a").Info("login")
+	l.With("user", "b").Info("login")
+	l.WithGroup("g").Info("login")
+	l.Info("login")
+	l.With("user", "a").Info("login")
+
+	if n := len(records(t, out.String())); n != 4 {
+		t.Errorf("wrote %d records, want one per derived logger", n)
+	}
+}
+
+func TestDedupFlush(t *testing.T) {
+	out := &syncBuffer{}
+	clock := newTestClock()
+	l, res := Open(WithWriter(out), WithDedup(time.Minute), WithClock(clock.Now))
+	t.Cleanup(func() { res.Close() })
+
+	l.Info("a")
+	l.Info("a")
+	l.Info("b")
+	if err := res.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	var summaries []string
+	for _, r := range records(t, out.String()) {
+		if r[DedupCountKey] != nil {
+			summaries = append(summaries, r["msg"].(string))
+		}
+	}
+	if len(summaries) != 1 || summaries[0] != "a" {
+		t.Errorf("Flush wrote summaries for %v, want only a", summaries)
+	}
+
+	l.Info("a")
+	if n := len(records(t, out.String())); n != 4 {
+		t.Errorf("after Flush a repeat is not logged fresh: %d records", n)
+	}
+}
@@ -0,0 +1,51 @@
+package logger
+
+import (
+	"log/slog"
+	"strings"
+)
+
+// ecsVersion is the Elastic Common Schema version the ECS format targets.
+const ecsVersion = "8.11.0"
+
+// ecsKeys maps top-level keys produced by this package to their ECS names.
+var ecsKeys = map[string]string{
+	slog.TimeKey:    "@timestamp",
+	slog.MessageKey: "message",
+	"trace_id":      "trace.id",
+	"span_id":       "span.id",
+	HostKey:         "host.hostname",
+	PIDKey:          "process.pid",
+}
+
+// replaceECS renames the built-in and correlation attributes to ECS fields.
+// User attributes named like a built-in are tagged by keysHandler and kept.
+func replaceECS(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) > 0 {
+		return a
+	}
+
+	switch a.Key {
+	case slog.LevelKey:
+		// ECS expects lowercase level names
+		name := a.Value.String()
+		if l, ok := a.Value.Any().(slog.Level); ok {
+			name = LevelName(l)
+		}
+		return slog.String("log.level", strings.ToLower(name))
+	case slog.SourceKey:
+		if src, ok := a.Value.Any().(*slog.Source); ok {
+			return slog.Group("log.origin",
+				slog.String("file.name", src.File),
+				slog.Int("file.line", src.Line),
+				slog.String("function", src.Function),
+			)
+		}
+	}
+
+	if key, ok := ecsKeys[a.Key]; ok {
+		a.Key = key
+	}
+
+	return a
+}
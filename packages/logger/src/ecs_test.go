@@ -0,0 +1,38 @@
+package logger
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestECSLeavesUserAttrs(t *testing.T) {
+	out := &syncBuffer{}
+	New(WithWriter(out), WithFormat(FormatECS)).With("source", "billing").
+		Info("hi", "msg", "user", "level", "custom", "time", "yesterday", "trace_id", "abc")
+
+	line := out.String()
+	for _, key := range []string{`"message":`, `"log.level":`, `"@timestamp":`} {
+		if n := strings.Count(line, key); n != 1 {
+			t.Errorf("%d %s keys in %s", n, key, line)
+		}
+	}
+	if strings.Contains(line, userKeyMarker) {
+		t.Errorf("marker leaked into %q", line)
+	}
+
+	r := records(t, line)[0]
+	want := map[string]any{
+		"message":   "hi",
+		"log.level": "info",
+		"msg":       "user",
+		"level":     "custom",
+		"time":      "yesterday",
+		"source":    "billing",
+		"trace.id":  "abc",
+	}
+	for k, v := range want {
+		if r[k] != v {
+			t.Errorf("%s = %v, want %v", k, r[k], v)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// Environment variables read at startup.
+const (
+	// LevelEnv sets the initial level (e.g. "debug", "warn", "-4").
+	LevelEnv = "LOG_LEVEL"
+	// FormatEnv selects the output format ("json", "text", "ecs" or "logfmt").
+	FormatEnv = "LOG_FORMAT"
+	// SourceEnv enables the source file:line attribute when true.
+	SourceEnv = "LOG_SOURCE"
+
+	// ServiceNameEnv, ServiceVersionEnv and DeployEnvEnv fill in any ServiceInfo
+	// fields left empty.
+	ServiceNameEnv    = "SERVICE_NAME"
+	ServiceVersionEnv = "SERVICE_VERSION"
+	DeployEnvEnv      = "DEPLOY_ENV"
+)
+
+// envOptions translates the LOG_* environment variables into Options for the
+// default logger. Invalid values fall back to the defaults and are returned as
+// errors so the caller can report them once a logger exists.
+func envOptions() ([]Option, []error) {
+	var (
+		opts []Option
+		errs []error
+	)
+
+	if v := os.Getenv(LevelEnv); v != "" {
+		l, err := ParseLevel(v)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("logger: invalid %s %q, defaulting to info", LevelEnv, v))
+		} else {
+			level.Set(l)
+		}
+	}
+
+	if v := os.Getenv(FormatEnv); v != "" {
+		f, err := ParseFormat(v)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("logger: invalid %s %q, defaulting to json", FormatEnv, v))
+		} else {
+			opts = append(opts, WithFormat(f))
+		}
+	}
+
+	if v := os.Getenv(SourceEnv); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("logger: invalid %s %q, defaulting to false", SourceEnv, v))
+		} else {
+			opts = append(opts, WithAddSource(b))
+		}
+	}
+
+	return opts, errs
+}
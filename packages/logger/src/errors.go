@@ -0,0 +1,79 @@
+package logger
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+)
+
+// ErrorKey is the attribute key used by Err.
+const ErrorKey = "error"
+
+// maxErrorDepth bounds how far Err follows an unwrap chain.
+const maxErrorDepth = 16
+
+// Err returns a structured "error" attribute for err: its message and type,
+// its code when it has a Code method, and what it wraps, nested under
+// "cause" (or "causes" for errors joined with errors.Join). A nil error
+// yields an empty attribute, which handlers omit.
+//
+//	logger.Error(ctx, "charge failed", logger.Err(err))
+//
+// The value is a slog.LogValuer whose Unwrap method returns err, so handlers
+// that need the original error (e.g. to report an exception) can recover it.
+func Err(err error) slog.Attr {
+	if err == nil {
+		return slog.Attr{}
+	}
+
+	return slog.Any(ErrorKey, errorAttr{err: err})
+}
+
+// errorAttr defers rendering an error until a handler formats it.
+type errorAttr struct {
+	err error
+}
+
+func (e errorAttr) LogValue() slog.Value {
+	return errorValue(e.err, 0)
+}
+
+func (e errorAttr) Unwrap() error {
+	return e.err
+}
+
+// errorValue renders err and its chain as a group.
+func errorValue(err error, depth int) slog.Value {
+	attrs := []slog.Attr{
+		slog.String("msg", err.Error()),
+		slog.String("type", fmt.Sprintf("%T", err)),
+	}
+
+	switch c := err.(type) {
+	case interface{ Code() string }:
+		attrs = append(attrs, slog.String("code", c.Code()))
+	case interface{ Code() int }:
+		attrs = append(attrs, slog.Int("code", c.Code()))
+	}
+
+	if depth < maxErrorDepth {
+		switch u := err.(type) {
+		case interface{ Unwrap() error }:
+			if cause := u.Unwrap(); cause != nil {
+				attrs = append(attrs, slog.Attr{Key: "cause", Value: errorValue(cause, depth+1)})
+			}
+		case interface{ Unwrap() []error }:
+			var causes []slog.Attr
+			for i, cause := range u.Unwrap() {
+				if cause != nil {
+					causes = append(causes, slog.Attr{Key: strconv.Itoa(i), Value: errorValue(cause, depth+1)})
+				}
+			}
+			if len(causes) > 0 {
+				attrs = append(attrs, slog.Attr{Key: "causes", Value: slog.GroupValue(causes...)})
+			}
+		}
+	}
+
+	return slog.GroupValue(attrs...)
+}
@@ -0,0 +1,43 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+)
+
+const fieldsKey contextKey = "fields"
+
+// WithFields returns a context carrying the given key-value pairs or
+// slog.Attrs. Every logger obtained through Ctx from that context includes
+// them. Nested calls append to the fields already present, so each middleware
+// layer can add its own.
+func WithFields(ctx context.Context, args ...any) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	// Let slog normalize the arguments exactly as Logger.With would
+	var r slog.Record
+	r.Add(args...)
+	if r.NumAttrs() == 0 {
+		return ctx
+	}
+
+	// Copy so sibling contexts derived from the same parent never share a
+	// backing array
+	parent := fieldsFromContext(ctx)
+	fields := make([]slog.Attr, len(parent), len(parent)+r.NumAttrs())
+	copy(fields, parent)
+	r.Attrs(func(a slog.Attr) bool {
+		fields = append(fields, a)
+		return true
+	})
+
+	return context.WithValue(ctx, fieldsKey, fields)
+}
+
+// fieldsFromContext returns the attributes accumulated by WithFields.
+func fieldsFromContext(ctx context.Context) []slog.Attr {
+	fields, _ := ctx.Value(fieldsKey).([]slog.Attr)
+	return fields
+}
@@ -0,0 +1,52 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Format selects the encoding used for log records.
+type Format int
+
+const (
+	// FormatJSON emits one JSON object per record. It is the default.
+	FormatJSON Format = iota
+	// FormatText emits slog's key=value text format.
+	FormatText
+	// FormatECS emits JSON using Elastic Common Schema field names
+	// (@timestamp, log.level, message, trace.id).
+	FormatECS
+	// FormatLogfmt emits logfmt; group members are flattened to group.key.
+	FormatLogfmt
+)
+
+// String returns the lowercase name of the format.
+func (f Format) String() string {
+	switch f {
+	case FormatText:
+		return "text"
+	case FormatECS:
+		return "ecs"
+	case FormatLogfmt:
+		return "logfmt"
+	default:
+		return "json"
+	}
+}
+
+// ParseFormat converts "json", "text", "ecs" or "logfmt" (case-insensitive)
+// into a Format.
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "json":
+		return FormatJSON, nil
+	case "text":
+		return FormatText, nil
+	case "ecs":
+		return FormatECS, nil
+	case "logfmt":
+		return FormatLogfmt, nil
+	default:
+		return FormatJSON, fmt.Errorf("logger: invalid format %q", s)
+	}
+}
@@ -0,0 +1,273 @@
+package logger
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net"
+	"os"
+	"strings"
+	"sync"
+)
+
+// gelfMaxChunks is the most chunks a GELF message may be split into.
+const gelfMaxChunks = 128
+
+// gelfChunkHeader is the magic bytes, message ID, sequence and count.
+const gelfChunkHeader = 12
+
+// ErrGELFTooLarge is returned when a UDP message needs more than 128 chunks.
+var ErrGELFTooLarge = errors.New("logger: gelf message too large")
+
+// GELFOptions configures NewGELFHandler.
+type GELFOptions struct {
+	// Host is reported as the GELF host; the machine's hostname when empty.
+	Host string
+	// Level is the minimum level handled; Info when nil.
+	Level slog.Leveler
+	// AddSource adds the caller as _file and _line.
+	AddSource bool
+	// ChunkSize is the largest UDP datagram sent, 1420 bytes when zero.
+	// Larger messages are split into GELF chunks.
+	ChunkSize int
+}
+
+// GELFHandler sends records to Graylog as GELF 1.1 messages over UDP or TCP.
+// The message becomes short_message, the level a syslog severity, and every
+// attribute, including trace_id, an additional field prefixed with "_".
+// Groups are flattened to _group.key. Values other than numbers are sent as
+// strings, the only other type GELF accepts.
+type GELFHandler struct {
+	conn *gelfConn
+	opts GELFOptions
+
+	// fields are the additional fields added by WithAttrs.
+	fields map[string]any
+	prefix string
+}
+
+// gelfConn is the connection shared by a handler and its children.
+type gelfConn struct {
+	network, addr string
+
+	mu sync.Mutex
+	c  net.Conn
+}
+
+// NewGELFHandler dials a Graylog input at addr over network, "udp" or "tcp".
+// A nil opts uses the defaults.
+func NewGELFHandler(network, addr string, opts *GELFOptions) (*GELFHandler, error) {
+	var o GELFOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.Host == "" {
+		o.Host, _ = os.Hostname()
+	}
+	if o.ChunkSize <= gelfChunkHeader {
+		o.ChunkSize = 1420
+	}
+
+	c, err := net.Dial(network, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GELFHandler{conn: &gelfConn{network: network, addr: addr, c: c}, opts: o}, nil
+}
+
+func (h *GELFHandler) Enabled(_ context.Context, level slog.Level) bool {
+	min := slog.LevelInfo
+	if h.opts.Level != nil {
+		min = h.opts.Level.Level()
+	}
+
+	return level >= min
+}
+
+func (h *GELFHandler) Handle(_ context.Context, r slog.Record) error {
+	msg := make(map[string]any, len(h.fields)+r.NumAttrs()+6)
+	for k, v := range h.fields {
+		msg[k] = v
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		addGELFField(msg, h.prefix, a)
+		return true
+	})
+	if h.opts.AddSource && r.PC != 0 {
+		src := recordSource(r)
+		msg["_file"] = src.File
+		msg["_line"] = src.Line
+	}
+
+	msg["version"] = "1.1"
+	msg["host"] = h.opts.Host
+	msg["short_message"] = r.Message
+	msg["level"] = gelfLevel(r.Level)
+	if !r.Time.IsZero() {
+		msg["timestamp"] = float64(r.Time.UnixMilli()) / 1e3
+	}
+
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	return h.conn.send(b, h.opts.ChunkSize)
+}
+
+func (h *GELFHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+
+	h2 := *h
+	h2.fields = make(map[string]any, len(h.fields)+len(attrs))
+	for k, v := range h.fields {
+		h2.fields[k] = v
+	}
+	for _, a := range attrs {
+		addGELFField(h2.fields, h.prefix, a)
+	}
+
+	return &h2
+}
+
+func (h *GELFHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	h2 := *h
+	h2.prefix = h.prefix + name + "."
+
+	return &h2
+}
+
+// Close closes the connection to Graylog.
+func (h *GELFHandler) Close() error {
+	h.conn.mu.Lock()
+	defer h.conn.mu.Unlock()
+
+	return h.conn.c.Close()
+}
+
+// addGELFField stores a as an additional field, flattening groups.
+func addGELFField(fields map[string]any, prefix string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			addGELFField(fields, prefix, ga)
+		}
+		return
+	}
+
+	key := "_" + gelfKey(prefix+a.Key)
+	// _id is reserved by Graylog
+	if key == "_id" {
+		key = "__id"
+	}
+
+	switch a.Value.Kind() {
+	case slog.KindInt64:
+		fields[key] = a.Value.Int64()
+	case slog.KindUint64:
+		fields[key] = a.Value.Uint64()
+	case slog.KindFloat64:
+		fields[key] = a.Value.Float64()
+	case slog.KindDuration:
+		fields[key] = a.Value.Duration().Milliseconds()
+	default:
+		fields[key] = logfmtValue(a.Value)
+	}
+}
+
+// gelfKey replaces characters GELF field names may not contain.
+func gelfKey(k string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9',
+			r == '_', r == '.', r == '-':
+			return r
+		}
+		return '_'
+	}, k)
+}
+
+// gelfLevel maps a slog level to a syslog severity.
+func gelfLevel(l slog.Level) int {
+	switch {
+	case l >= slog.LevelError:
+		return 3
+	case l >= slog.LevelWarn:
+		return 4
+	case l >= slog.LevelInfo:
+		return 6
+	default:
+		return 7
+	}
+}
+
+// send writes one message, chunking it over UDP and null-terminating it over
+// TCP. A failed write is retried once on a fresh connection.
+func (c *gelfConn) send(b []byte, chunkSize int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	err := c.write(b, chunkSize)
+	if err == nil || errors.Is(err, ErrGELFTooLarge) {
+		return err
+	}
+
+	conn, dialErr := net.Dial(c.network, c.addr)
+	if dialErr != nil {
+		return err
+	}
+	c.c.Close()
+	c.c = conn
+
+	return c.write(b, chunkSize)
+}
+
+func (c *gelfConn) write(b []byte, chunkSize int) error {
+	if !strings.HasPrefix(c.network, "udp") {
+		_, err := c.c.Write(append(b, 0))
+		return err
+	}
+
+	if len(b) <= chunkSize {
+		_, err := c.c.Write(b)
+		return err
+	}
+
+	size := chunkSize - gelfChunkHeader
+	count := (len(b) + size - 1) / size
+	if count > gelfMaxChunks {
+		return ErrGELFTooLarge
+	}
+
+	chunk := make([]byte, gelfChunkHeader, chunkSize)
+	chunk[0], chunk[1] = 0x1e, 0x0f
+	if _, err := rand.Read(chunk[2:10]); err != nil {
+		return err
+	}
+	chunk[11] = byte(count)
+	for i := 0; i < count; i++ {
+		end := min((i+1)*size, len(b))
+		chunk[10] = byte(i)
+		if _, err := c.c.Write(append(chunk[:gelfChunkHeader], b[i*size:end]...)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
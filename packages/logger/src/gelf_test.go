@@ -0,0 +1,171 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// listenUDP returns a local UDP listener, closed when the test ends.
+func listenUDP(t *testing.T) net.PacketConn {
+	t.Helper()
+
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { pc.Close() })
+
+	return pc
+}
+
+// readDatagrams reads n datagrams from pc.
+func readDatagrams(t *testing.T, pc net.PacketConn, n int) [][]byte {
+	t.Helper()
+
+	pc.SetReadDeadline(time.Now().Add(2 * time.Second))
+	out := make([][]byte, 0, n)
+	buf := make([]byte, 65536)
+	for range n {
+		m, _, err := pc.ReadFrom(buf)
+		if err != nil {
+			t.Fatalf("read datagram %d of %d: %v", len(out)+1, n, err)
+		}
+		out = append(out, bytes.Clone(buf[:m]))
+	}
+
+	return out
+}
+
+func newTestGELF(t *testing.T, network, addr string, opts *GELFOptions) *GELFHandler {
+	t.Helper()
+
+	h, err := NewGELFHandler(network, addr, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { h.Close() })
+
+	return h
+}
+
+func TestGELFMessage(t *testing.T) {
+	pc := listenUDP(t)
+	h := newTestGELF(t, "udp", pc.LocalAddr().String(), &GELFOptions{Host: "web-1"})
+
+	slog.New(h).With("id", "x").WithGroup("req").Warn("hi", "n", 3, "d", 1500*time.Millisecond, "path", "/a b")
+
+	var msg map[string]any
+	if err := json.Unmarshal(readDatagrams(t, pc, 1)[0], &msg); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"version":       "1.1",
+		"host":          "web-1",
+		"short_message": "hi",
+		"level":         float64(4),
+		"__id":          "x",
+		"_req.n":        float64(3),
+		"_req.d":        float64(1500),
+		"_req.path":     "/a b",
+	}
+	for k, v := range want {
+		if msg[k] != v {
+			t.Errorf("%s = %v, want %v", k, msg[k], v)
+		}
+	}
+}
+
+func TestGELFChunks(t *testing.T) {
+	pc := listenUDP(t)
+	h := newTestGELF(t, "udp", pc.LocalAddr().String(), &GELFOptions{ChunkSize: 100})
+
+	big := strings.Repeat("x", 1000)
+	r := slog.NewRecord(time.Now(), slog.LevelInfo, "big", 0)
+	r.AddAttrs(slog.String("payload", big))
+	if err := h.Handle(context.Background(), r); err != nil {
+		t.Fatal(err)
+	}
+
+	first := readDatagrams(t, pc, 1)[0]
+	if len(first) < gelfChunkHeader || first[0] != 0x1e || first[1] != 0x0f {
+		t.Fatalf("datagram %q is not a GELF chunk", first)
+	}
+	count := int(first[11])
+	chunks := append([][]byte{first}, readDatagrams(t, pc, count-1)...)
+
+	parts := make([][]byte, count)
+	for _, c := range chunks {
+		if len(c) > 100 {
+			t.Errorf("chunk of %d bytes exceeds ChunkSize", len(c))
+		}
+		if !bytes.Equal(c[2:10], first[2:10]) {
+			t.Error("chunks carry different message IDs")
+		}
+		if int(c[11]) != count {
+			t.Errorf("chunk count %d, want %d", c[11], count)
+		}
+		parts[c[10]] = c[gelfChunkHeader:]
+	}
+
+	var msg map[string]any
+	if err := json.Unmarshal(bytes.Join(parts, nil), &msg); err != nil {
+		t.Fatalf("reassembled message: %v", err)
+	}
+	if msg["_payload"] != big {
+		t.Error("reassembled payload differs")
+	}
+}
+
+func TestGELFTooLarge(t *testing.T) {
+	pc := listenUDP(t)
+	h := newTestGELF(t, "udp", pc.LocalAddr().String(), &GELFOptions{ChunkSize: gelfChunkHeader + 8})
+
+	r := slog.NewRecord(time.Now(), slog.LevelInfo, "big", 0)
+	r.AddAttrs(slog.String("payload", strings.Repeat("x", gelfMaxChunks*8)))
+	if err := h.Handle(context.Background(), r); !errors.Is(err, ErrGELFTooLarge) {
+		t.Errorf("Handle = %v, want ErrGELFTooLarge", err)
+	}
+}
+
+func TestGELFTCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	h := newTestGELF(t, "tcp", ln.Addr().String(), nil)
+	conn, err := ln.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	l := slog.New(h)
+	l.Info("one")
+	l.Info("two")
+
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	br := bufio.NewReader(conn)
+	for _, want := range []string{"one", "two"} {
+		frame, err := br.ReadBytes(0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var msg map[string]any
+		if err := json.Unmarshal(frame[:len(frame)-1], &msg); err != nil {
+			t.Fatalf("frame %q: %v", frame, err)
+		}
+		if msg["short_message"] != want {
+			t.Errorf("short_message = %v, want %s", msg["short_message"], want)
+		}
+	}
+}
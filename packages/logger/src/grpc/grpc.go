@@ -0,0 +1,135 @@
+// Package grpc provides gRPC interceptors that propagate the logger's trace ID
+// across service boundaries and log each call on completion.
+package grpc
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	logger "fuelers-go/packages/logger/src"
+)
+
+// Metadata keys carrying the trace ID. Incoming calls are checked in this
+// order; outgoing calls carry TraceIDKey.
+const (
+	TraceIDKey   = "x-trace-id"
+	RequestIDKey = "x-request-id"
+)
+
+// UnaryServerInterceptor correlates each unary call with a trace ID from the
+// incoming metadata, or a generated one, and logs its completion.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		ctx = withIncomingCorrelation(ctx)
+
+		resp, err := handler(ctx, req)
+		logCall(ctx, info.FullMethod, err, time.Since(start))
+
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor correlates each stream with a trace ID from the
+// incoming metadata, or a generated one, and logs its completion.
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		ctx := withIncomingCorrelation(ss.Context())
+
+		err := handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+		logCall(ctx, info.FullMethod, err, time.Since(start))
+
+		return err
+	}
+}
+
+// UnaryClientInterceptor forwards the context's trace ID in the outgoing
+// metadata.
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(withOutgoingCorrelation(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+// StreamClientInterceptor forwards the context's trace ID in the outgoing
+// metadata.
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(withOutgoingCorrelation(ctx), desc, cc, method, opts...)
+	}
+}
+
+// withIncomingCorrelation stores the caller's trace ID on ctx, generating one
+// when the metadata has none.
+func withIncomingCorrelation(ctx context.Context) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, key := range []string{TraceIDKey, RequestIDKey} {
+		if v := md.Get(key); len(v) > 0 {
+			// The ID may be rejected by the logger's trace ID policy
+			if c := logger.WithCorrelation(ctx, v[0]); c != ctx {
+				return c
+			}
+		}
+	}
+
+	ctx, _ = logger.WithGeneratedCorrelation(ctx)
+	return ctx
+}
+
+// withOutgoingCorrelation appends the context's trace ID to the outgoing
+// metadata.
+func withOutgoingCorrelation(ctx context.Context) context.Context {
+	id, ok := logger.TraceIDFromContext(ctx)
+	if !ok || id == "" {
+		return ctx
+	}
+
+	return metadata.AppendToOutgoingContext(ctx, TraceIDKey, id)
+}
+
+// logCall writes the per-call summary line at a level derived from the code.
+func logCall(ctx context.Context, method string, err error, d time.Duration) {
+	code := status.Code(err)
+	attrs := []slog.Attr{
+		slog.String("method", method),
+		slog.String("code", code.String()),
+		logger.Duration("duration", d),
+	}
+	if err != nil {
+		attrs = append(attrs, logger.Err(err))
+	}
+
+	logger.Ctx(ctx).LogAttrs(ctx, codeLevel(code), "grpc request", attrs...)
+}
+
+// codeLevel maps a status code to a log level: client-side problems are info,
+// transient or policy failures are warnings and server faults are errors.
+func codeLevel(code codes.Code) slog.Level {
+	switch code {
+	case codes.OK, codes.Canceled, codes.InvalidArgument, codes.NotFound,
+		codes.AlreadyExists, codes.Unauthenticated:
+		return slog.LevelInfo
+	case codes.DeadlineExceeded, codes.PermissionDenied, codes.ResourceExhausted,
+		codes.FailedPrecondition, codes.Aborted, codes.OutOfRange, codes.Unavailable:
+		return slog.LevelWarn
+	default:
+		return slog.LevelError
+	}
+}
+
+// serverStream overrides the stream context so handlers see the correlation.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,104 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+)
+
+// HookFunc is called for each emitted record at or above its hook's level.
+type HookFunc func(ctx context.Context, r slog.Record)
+
+// hook pairs a callback with its threshold.
+type hook struct {
+	level slog.Level
+	fn    HookFunc
+}
+
+// hooks is the copy-on-write list consulted by every logger built with New.
+var (
+	hooksMu sync.Mutex
+	hooks   atomic.Pointer[[]*hook]
+)
+
+// RegisterHook calls fn synchronously for every record at or above level that
+// a logger built by this package writes, after it has been written and
+// redacted. With WithAsync, hooks run on the background writer. A panicking
+// hook is recovered and passed to the error reporter. The returned function
+// removes the hook.
+func RegisterHook(level slog.Level, fn HookFunc) (unregister func()) {
+	h := &hook{level: level, fn: fn}
+
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+
+	var next []*hook
+	if cur := hooks.Load(); cur != nil {
+		next = append(next, *cur...)
+	}
+	next = append(next, h)
+	hooks.Store(&next)
+
+	return func() {
+		hooksMu.Lock()
+		defer hooksMu.Unlock()
+
+		cur := hooks.Load()
+		if cur == nil {
+			return
+		}
+		next := make([]*hook, 0, len(*cur))
+		for _, other := range *cur {
+			if other != h {
+				next = append(next, other)
+			}
+		}
+		hooks.Store(&next)
+	}
+}
+
+// hookHandler runs the registered hooks after the inner handler has written
+// a record.
+type hookHandler struct {
+	inner slog.Handler
+}
+
+func (h *hookHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+func (h *hookHandler) Handle(ctx context.Context, r slog.Record) error {
+	err := h.inner.Handle(ctx, r)
+
+	if hs := hooks.Load(); hs != nil {
+		for _, hk := range *hs {
+			if r.Level >= hk.level {
+				runHook(ctx, hk, r.Clone())
+			}
+		}
+	}
+
+	return err
+}
+
+func (h *hookHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &hookHandler{inner: h.inner.WithAttrs(attrs)}
+}
+
+func (h *hookHandler) WithGroup(name string) slog.Handler {
+	return &hookHandler{inner: h.inner.WithGroup(name)}
+}
+
+// runHook invokes one hook, recovering from panics so a faulty hook cannot
+// take down the caller.
+func runHook(ctx context.Context, hk *hook, r slog.Record) {
+	defer func() {
+		if v := recover(); v != nil {
+			reportError(fmt.Errorf("logger: hook panicked: %v", v))
+		}
+	}()
+
+	hk.fn(ctx, r)
+}
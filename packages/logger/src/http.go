@@ -0,0 +1,135 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Correlation headers understood by Middleware.
+const (
+	RequestIDHeader     = "X-Request-Id"
+	TraceIDHeader       = "X-Trace-Id"
+	CorrelationIDHeader = "X-Correlation-Id"
+	// TraceparentHeader is the W3C Trace Context header; its trace-id field
+	// is used.
+	TraceparentHeader = "Traceparent"
+)
+
+// TraceIDHeaders lists the headers Middleware takes the trace ID from, in
+// order of preference. Reorder or extend it at startup, before serving.
+var TraceIDHeaders = []string{RequestIDHeader, TraceIDHeader, TraceparentHeader, CorrelationIDHeader}
+
+// Middleware correlates each request with a trace ID and logs one line per
+// request. The ID is taken from the first of TraceIDHeaders present, or
+// generated when none is; it is stored with WithCorrelation and echoed in the
+// response under the header it came from, or X-Request-Id.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		header, id := traceIDFromHeaders(r.Header)
+		ctx := WithCorrelation(r.Context(), id)
+
+		// Echo the ID as stored, after normalization or replacement
+		if id, _ = TraceIDFromContext(ctx); id == "" {
+			ctx, id = WithGeneratedCorrelation(ctx)
+		}
+		w.Header().Set(header, id)
+
+		rw := &responseWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rw, r.WithContext(ctx))
+
+		logRequest(ctx, r, rw.status, time.Since(start))
+	})
+}
+
+// traceIDFromHeaders returns the first trace ID found in TraceIDHeaders and
+// the header to echo it in.
+func traceIDFromHeaders(h http.Header) (header, id string) {
+	for _, name := range TraceIDHeaders {
+		v := strings.TrimSpace(h.Get(name))
+		if v == "" {
+			continue
+		}
+		if http.CanonicalHeaderKey(name) == TraceparentHeader {
+			// A traceparent is not an ID to echo back as is
+			if id, ok := parseTraceparent(v); ok {
+				return RequestIDHeader, id
+			}
+			continue
+		}
+		return name, v
+	}
+
+	return RequestIDHeader, ""
+}
+
+// parseTraceparent returns the trace-id field of a W3C traceparent value,
+// "version-traceid-parentid-flags", rejecting malformed and all-zero IDs.
+func parseTraceparent(v string) (string, bool) {
+	parts := strings.Split(v, "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" {
+		return "", false
+	}
+	// Version 00 has exactly four fields; later versions may append more
+	if parts[0] == "00" && len(parts) != 4 {
+		return "", false
+	}
+
+	id := strings.ToLower(parts[1])
+	if !HexTraceID(32)(id) || id == strings.Repeat("0", 32) {
+		return "", false
+	}
+
+	return id, true
+}
+
+// logRequest writes the per-request summary line, at error level for 5xx.
+func logRequest(ctx context.Context, r *http.Request, status int, d time.Duration) {
+	level := slog.LevelInfo
+	if status >= http.StatusInternalServerError {
+		level = slog.LevelError
+	}
+
+	Ctx(ctx).LogAttrs(ctx, level, "http request",
+		slog.String("method", r.Method),
+		slog.String("path", r.URL.Path),
+		slog.Int("status", status),
+		Duration("duration", d),
+	)
+}
+
+// responseWriter records the status code written by the wrapped handler.
+type responseWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (w *responseWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.status = code
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush lets streaming handlers flush through the wrapper.
+func (w *responseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController.
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
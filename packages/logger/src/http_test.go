@@ -0,0 +1,111 @@
+package logger
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRequestDurationInDurationUnit(t *testing.T) {
+	out := &syncBuffer{}
+	ctx := WithLogger(context.Background(), New(WithWriter(out)))
+
+	logRequest(ctx, httptest.NewRequest("GET", "/x", nil), 200, 1500*time.Microsecond)
+
+	if got := records(t, out.String())[0]["duration"]; got != 1.5 {
+		t.Errorf("duration = %v, want 1.5 (ms)", got)
+	}
+}
+
+func TestParseTraceparent(t *testing.T) {
+	const id = "4bf92f3577b34da6a3ce929d0e0e4736"
+	tests := []struct {
+		name, in, want string
+		ok             bool
+	}{
+		{"valid", "00-" + id + "-00f067aa0ba902b7-01", id, true},
+		{"uppercase hex", "00-" + strings.ToUpper(id) + "-00f067aa0ba902b7-01", id, true},
+		{"future version with extra fields", "01-" + id + "-00f067aa0ba902b7-01-extra", id, true},
+		{"all-zero trace ID", "00-" + strings.Repeat("0", 32) + "-00f067aa0ba902b7-01", "", false},
+		{"invalid version", "ff-" + id + "-00f067aa0ba902b7-01", "", false},
+		{"long version", "000-" + id + "-00f067aa0ba902b7-01", "", false},
+		{"version 00 with extra fields", "00-" + id + "-00f067aa0ba902b7-01-extra", "", false},
+		{"short trace ID", "00-" + id[:31] + "-00f067aa0ba902b7-01", "", false},
+		{"non-hex trace ID", "00-" + id[:31] + "g-00f067aa0ba902b7-01", "", false},
+		{"missing fields", "00-" + id, "", false},
+	}
+	for _, tt := range tests {
+		got, ok := parseTraceparent(tt.in)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("%s: parseTraceparent(%q) = %q, %v, want %q, %v", tt.name, tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestTraceIDHeaderPrecedence(t *testing.T) {
+	const id = "4bf92f3577b34da6a3ce929d0e0e4736"
+	traceparent := "00-" + id + "-00f067aa0ba902b7-01"
+
+	tests := []struct {
+		name       string
+		order      []string
+		headers    map[string]string
+		wantHeader string
+		wantID     string
+	}{
+		{"request ID first", TraceIDHeaders, map[string]string{
+			RequestIDHeader: "req", TraceIDHeader: "trace", TraceparentHeader: traceparent,
+		}, RequestIDHeader, "req"},
+		{"traceparent before correlation ID", TraceIDHeaders, map[string]string{
+			TraceparentHeader: traceparent, CorrelationIDHeader: "corr",
+		}, RequestIDHeader, id},
+		{"invalid traceparent skipped", TraceIDHeaders, map[string]string{
+			TraceparentHeader: "garbage", CorrelationIDHeader: "corr",
+		}, CorrelationIDHeader, "corr"},
+		{"reordered", []string{CorrelationIDHeader, RequestIDHeader}, map[string]string{
+			RequestIDHeader: "req", CorrelationIDHeader: "corr",
+		}, CorrelationIDHeader, "corr"},
+		{"none", TraceIDHeaders, nil, RequestIDHeader, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			saved := TraceIDHeaders
+			TraceIDHeaders = tt.order
+			t.Cleanup(func() { TraceIDHeaders = saved })
+
+			h := http.Header{}
+			for k, v := range tt.headers {
+				h.Set(k, v)
+			}
+			header, id := traceIDFromHeaders(h)
+			if header != tt.wantHeader || id != tt.wantID {
+				t.Errorf("got %s=%q, want %s=%q", header, id, tt.wantHeader, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestMiddlewareEchoesTraceparentID(t *testing.T) {
+	const id = "4bf92f3577b34da6a3ce929d0e0e4736"
+	out := &syncBuffer{}
+	l := New(WithWriter(out))
+
+	var got string
+	h := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = TraceIDFromContext(r.Context())
+	}))
+	req := httptest.NewRequest("GET", "/", nil).WithContext(WithLogger(context.Background(), l))
+	req.Header.Set(TraceparentHeader, "00-"+id+"-00f067aa0ba902b7-01")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if got != id || rec.Header().Get(RequestIDHeader) != id {
+		t.Errorf("stored %q, echoed %q, want %q", got, rec.Header().Get(RequestIDHeader), id)
+	}
+	if r := records(t, out.String()); len(r) != 1 || r[0]["trace_id"] != id {
+		t.Errorf("request line %v, want trace_id %s", r, id)
+	}
+}
@@ -0,0 +1,146 @@
+package logger
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"sync/atomic"
+)
+
+// traceIDGenerator holds the function used by NewTraceID.
+var traceIDGenerator atomic.Pointer[func() string]
+
+// traceIDPolicy holds the policy applied by WithCorrelation.
+var traceIDPolicy atomic.Pointer[TraceIDPolicy]
+
+// InvalidTraceID selects what WithCorrelation does with an ID that fails
+// TraceIDPolicy.Validate.
+type InvalidTraceID int
+
+const (
+	// InvalidTraceIDKeep stores the ID anyway. It is the default.
+	InvalidTraceIDKeep InvalidTraceID = iota
+	// InvalidTraceIDReject leaves the context without a trace ID.
+	InvalidTraceIDReject
+	// InvalidTraceIDReplace stores a NewTraceID instead.
+	InvalidTraceIDReplace
+)
+
+// TraceIDPolicy normalizes and validates the IDs given to WithCorrelation,
+// which are typically taken from untrusted request headers. Surrounding
+// whitespace is always trimmed and empty IDs are never stored.
+type TraceIDPolicy struct {
+	// Normalize rewrites the trimmed ID, e.g. strings.ToLower.
+	Normalize func(string) string
+	// Validate reports whether the normalized ID is acceptable.
+	Validate func(string) bool
+	// OnInvalid applies when Validate returns false.
+	OnInvalid InvalidTraceID
+}
+
+// SetTraceIDPolicy replaces the policy applied by WithCorrelation. The zero
+// policy, the default, only trims and skips empty IDs. The generator set
+// with SetTraceIDGenerator should produce IDs the policy accepts. It is safe
+// for concurrent use.
+func SetTraceIDPolicy(p TraceIDPolicy) {
+	traceIDPolicy.Store(&p)
+}
+
+// HexTraceID returns a validator accepting lowercase hex IDs of exactly n
+// characters, such as 32 for W3C trace IDs. Pair it with strings.ToLower as
+// the Normalize function to accept uppercase input.
+func HexTraceID(n int) func(string) bool {
+	return func(id string) bool {
+		if len(id) != n {
+			return false
+		}
+		for i := 0; i < len(id); i++ {
+			c := id[i]
+			if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// normalizeTraceID applies the trace ID policy, reporting false when id must
+// not be stored.
+func normalizeTraceID(id string) (string, bool) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return "", false
+	}
+
+	p := traceIDPolicy.Load()
+	if p == nil {
+		return id, true
+	}
+	if p.Normalize != nil {
+		id = p.Normalize(id)
+	}
+	if p.Validate == nil || p.Validate(id) {
+		return id, id != ""
+	}
+
+	switch p.OnInvalid {
+	case InvalidTraceIDReject:
+		return "", false
+	case InvalidTraceIDReplace:
+		return NewTraceID(), true
+	}
+
+	return id, true
+}
+
+// NewTraceID returns a fresh trace ID from the configured generator. The
+// default produces random version 4 UUIDs.
+func NewTraceID() string {
+	if gen := traceIDGenerator.Load(); gen != nil {
+		return (*gen)()
+	}
+
+	return newUUID()
+}
+
+// SetTraceIDGenerator replaces the generator used by NewTraceID, e.g. to emit
+// ULIDs. Passing nil restores the default. It is safe for concurrent use.
+func SetTraceIDGenerator(fn func() string) {
+	if fn == nil {
+		traceIDGenerator.Store(nil)
+		return
+	}
+
+	traceIDGenerator.Store(&fn)
+}
+
+// WithGeneratedCorrelation generates a trace ID, stores it on the context like
+// WithCorrelation, and returns both.
+func WithGeneratedCorrelation(ctx context.Context) (context.Context, string) {
+	ctx = WithCorrelation(ctx, NewTraceID())
+	id, _ := TraceIDFromContext(ctx)
+	return ctx, id
+}
+
+// newUUID returns a random RFC 4122 version 4 UUID. It formats into a stack
+// buffer so the returned string is the only allocation.
+func newUUID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40 // version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // RFC 4122 variant
+
+	var out [36]byte
+	hex.Encode(out[0:8], b[0:4])
+	out[8] = '-'
+	hex.Encode(out[9:13], b[4:6])
+	out[13] = '-'
+	hex.Encode(out[14:18], b[6:8])
+	out[18] = '-'
+	hex.Encode(out[19:23], b[8:10])
+	out[23] = '-'
+	hex.Encode(out[24:], b[10:])
+
+	return string(out[:])
+}
@@ -0,0 +1,95 @@
+package logger
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const (
+	validID     = "4bf92f3577b34da6a3ce929d0e0e4736"
+	generatedID = "00000000000000000000000000000001"
+)
+
+// withPolicy installs p and a fixed generator for the rest of the test.
+func withPolicy(t *testing.T, p *TraceIDPolicy) {
+	t.Helper()
+
+	if p != nil {
+		SetTraceIDPolicy(*p)
+	}
+	SetTraceIDGenerator(func() string { return generatedID })
+	t.Cleanup(func() {
+		traceIDPolicy.Store(nil)
+		SetTraceIDGenerator(nil)
+	})
+}
+
+func TestTraceIDPolicy(t *testing.T) {
+	hex := func(on InvalidTraceID) *TraceIDPolicy {
+		return &TraceIDPolicy{Normalize: strings.ToLower, Validate: HexTraceID(32), OnInvalid: on}
+	}
+
+	tests := []struct {
+		name   string
+		policy *TraceIDPolicy
+		in     string
+		want   string // empty for no trace ID
+	}{
+		{"default empty", nil, "", ""},
+		{"default blank", nil, "  \t", ""},
+		{"default trims", nil, " abc ", "abc"},
+		{"default keeps anything", nil, "not hex", "not hex"},
+
+		{"keep empty", hex(InvalidTraceIDKeep), "", ""},
+		{"keep valid", hex(InvalidTraceIDKeep), validID, validID},
+		{"keep normalizes", hex(InvalidTraceIDKeep), " " + strings.ToUpper(validID) + " ", validID},
+		{"keep malformed", hex(InvalidTraceIDKeep), "xyz", "xyz"},
+
+		{"reject empty", hex(InvalidTraceIDReject), "", ""},
+		{"reject valid", hex(InvalidTraceIDReject), validID, validID},
+		{"reject malformed", hex(InvalidTraceIDReject), "xyz", ""},
+		{"reject short", hex(InvalidTraceIDReject), validID[:31], ""},
+
+		{"replace empty", hex(InvalidTraceIDReplace), "", ""},
+		{"replace valid", hex(InvalidTraceIDReplace), validID, validID},
+		{"replace malformed", hex(InvalidTraceIDReplace), "xyz", generatedID},
+
+		{"normalized to empty", &TraceIDPolicy{Normalize: func(string) string { return "" }}, "abc", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withPolicy(t, tt.policy)
+
+			got, ok := TraceIDFromContext(WithCorrelation(context.Background(), tt.in))
+			if got != tt.want || ok != (tt.want != "") {
+				t.Errorf("WithCorrelation(%q) stored %q, %v; want %q", tt.in, got, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestMiddlewareRejectedTraceID(t *testing.T) {
+	captureDefault(t)
+	withPolicy(t, &TraceIDPolicy{Validate: HexTraceID(32), OnInvalid: InvalidTraceIDReject})
+
+	var seen string
+	h := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen, _ = TraceIDFromContext(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(RequestIDHeader, "<script>")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if seen != generatedID {
+		t.Errorf("handler saw trace ID %q, want a generated one", seen)
+	}
+	if got := rec.Header().Get(RequestIDHeader); got != generatedID {
+		t.Errorf("echoed %q, want the stored ID", got)
+	}
+}
@@ -0,0 +1,175 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+)
+
+// userKeyMarker tags top-level user attributes named like a built-in that
+// WithMessageKey, WithLevelKey or FormatECS renames, so that only the
+// built-in is. The ReplaceAttr chain strips it before any other step.
+const userKeyMarker = "\x00user:"
+
+// WithMessageKey renames the built-in message attribute, e.g. to "message".
+func WithMessageKey(key string) Option {
+	return func(c *config) {
+		c.messageKey = key
+	}
+}
+
+// WithLevelKey renames the built-in level attribute, e.g. to "severity".
+func WithLevelKey(key string) Option {
+	return func(c *config) {
+		c.levelKey = key
+	}
+}
+
+// keysReplacer returns the ReplaceAttr step for WithMessageKey and
+// WithLevelKey, or nil when neither is set. It runs after every other step,
+// which all match the default keys, and is skipped for user attributes
+// tagged by keysHandler. Attribute values are untouched, so the level keeps
+// its TRACE naming. FormatECS has its own fixed keys.
+func (c *config) keysReplacer() func([]string, slog.Attr) slog.Attr {
+	if c.messageKey == "" && c.levelKey == "" {
+		return nil
+	}
+
+	return func(groups []string, a slog.Attr) slog.Attr {
+		if len(groups) > 0 {
+			return a
+		}
+
+		switch {
+		case a.Key == slog.MessageKey && c.messageKey != "":
+			a.Key = c.messageKey
+		case a.Key == slog.LevelKey && c.levelKey != "":
+			a.Key = c.levelKey
+		}
+		return a
+	}
+}
+
+// renamedKeys lists the built-in keys that keysReplacer or replaceECS
+// renames.
+func (c *config) renamedKeys() []string {
+	if c.format == FormatECS {
+		return []string{slog.TimeKey, slog.LevelKey, slog.MessageKey, slog.SourceKey}
+	}
+
+	var keys []string
+	if c.messageKey != "" {
+		keys = append(keys, slog.MessageKey)
+	}
+	if c.levelKey != "" {
+		keys = append(keys, slog.LevelKey)
+	}
+
+	return keys
+}
+
+// keysHandler tags top-level user attributes whose key is one of keys with
+// userKeyMarker, since ReplaceAttr cannot tell them from the built-ins. It
+// wraps only the package's own handlers, which strip the tag again.
+type keysHandler struct {
+	inner   slog.Handler
+	keys    []string
+	grouped bool
+}
+
+func (h *keysHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+func (h *keysHandler) Handle(ctx context.Context, r slog.Record) error {
+	if h.grouped {
+		return h.inner.Handle(ctx, r)
+	}
+
+	clash := false
+	r.Attrs(func(a slog.Attr) bool {
+		clash = h.clashes(a)
+		return !clash
+	})
+	if !clash {
+		return h.inner.Handle(ctx, r)
+	}
+
+	out := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		out.AddAttrs(h.tag(a))
+		return true
+	})
+
+	return h.inner.Handle(ctx, out)
+}
+
+func (h *keysHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if h.grouped {
+		return &keysHandler{inner: h.inner.WithAttrs(attrs), keys: h.keys, grouped: true}
+	}
+
+	tagged := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		tagged[i] = h.tag(a)
+	}
+
+	return &keysHandler{inner: h.inner.WithAttrs(tagged), keys: h.keys}
+}
+
+func (h *keysHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	return &keysHandler{inner: h.inner.WithGroup(name), keys: h.keys, grouped: true}
+}
+
+// clashes reports whether a, or a member of an inlined group, needs a tag.
+func (h *keysHandler) clashes(a slog.Attr) bool {
+	if a.Key == "" && a.Value.Kind() == slog.KindGroup {
+		for _, ga := range a.Value.Group() {
+			if h.clashes(ga) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, k := range h.keys {
+		if a.Key == k {
+			return true
+		}
+	}
+	return false
+}
+
+// tag marks a, descending into groups with an empty key, which are inlined
+// at the top level.
+func (h *keysHandler) tag(a slog.Attr) slog.Attr {
+	if a.Key == "" && a.Value.Kind() == slog.KindGroup {
+		members := a.Value.Group()
+		tagged := make([]slog.Attr, len(members))
+		for i, ga := range members {
+			tagged[i] = h.tag(ga)
+		}
+		return slog.Attr{Value: slog.GroupValue(tagged...)}
+	}
+
+	if h.clashes(a) {
+		a.Key = userKeyMarker + a.Key
+	}
+	return a
+}
+
+// untag strips userKeyMarker from a top-level key, reporting whether it was
+// there.
+func untag(groups []string, a slog.Attr) (slog.Attr, bool) {
+	if len(groups) > 0 {
+		return a, false
+	}
+
+	key, ok := strings.CutPrefix(a.Key, userKeyMarker)
+	a.Key = key
+	return a, ok
+}
@@ -0,0 +1,83 @@
+package logger
+
+import (
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestRenamedKeysLeaveUserAttrs(t *testing.T) {
+	out := &syncBuffer{}
+	l := New(WithWriter(out), WithMessageKey("message"), WithLevelKey("severity"))
+
+	l.With("msg", "with").Info("hi", "level", "user", slog.Group("g", "msg", "nested"), slog.Group("", "msg", "inline"))
+
+	line := out.String()
+	if n := strings.Count(line, `"message":`); n != 1 {
+		t.Errorf("%d message keys in %s", n, line)
+	}
+	if n := strings.Count(line, `"severity":`); n != 1 {
+		t.Errorf("%d severity keys in %s", n, line)
+	}
+	if strings.Contains(line, userKeyMarker) {
+		t.Errorf("marker leaked into %q", line)
+	}
+	for _, want := range []string{`"message":"hi"`, `"severity":"INFO"`, `"msg":"with"`, `"level":"user"`, `"g":{"msg":"nested"}`, `"msg":"inline"`} {
+		if !strings.Contains(line, want) {
+			t.Errorf("missing %s in %s", want, line)
+		}
+	}
+}
+
+func TestRenamedKeysLogfmt(t *testing.T) {
+	out := &syncBuffer{}
+	l := New(WithWriter(out), WithFormat(FormatLogfmt), WithMessageKey("message"))
+
+	l.WithGroup("req").With("msg", "nested").Info("hi", "msg", "user")
+
+	line := out.String()
+	for _, want := range []string{" message=hi ", " req.msg=nested", " req.msg=user"} {
+		if !strings.Contains(line, want) {
+			t.Errorf("missing %q in %q", want, line)
+		}
+	}
+
+	out2 := &syncBuffer{}
+	New(WithWriter(out2), WithFormat(FormatLogfmt), WithMessageKey("message")).Info("hi", "msg", "user")
+	if line := out2.String(); !strings.Contains(line, " message=hi ") || !strings.Contains(line, " msg=user") {
+		t.Errorf("top-level user msg renamed in %q", line)
+	}
+}
+
+func TestRenamedKeysUserReplacerSeesOriginalKey(t *testing.T) {
+	out := &syncBuffer{}
+	var seen []string
+	record := func(c *config) {
+		c.replacers = append(c.replacers, func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key == slog.MessageKey {
+				seen = append(seen, a.Value.String())
+			}
+			return a
+		})
+	}
+	l := New(WithWriter(out), WithMessageKey("message"), record)
+
+	l.Info("hi", "msg", "user")
+
+	if len(seen) != 2 {
+		t.Errorf("replacer saw msg keys %v, want the built-in and the user attribute", seen)
+	}
+}
+
+func TestRenamedKeysWithoutClash(t *testing.T) {
+	out := &syncBuffer{}
+	New(WithWriter(out), WithMessageKey("message")).Info("hi", "k", "v")
+
+	recs := records(t, out.String())
+	if len(recs) != 1 || recs[0]["message"] != "hi" || recs[0]["k"] != "v" {
+		t.Errorf("got %v", recs)
+	}
+	if _, ok := recs[0][slog.MessageKey]; ok {
+		t.Error("built-in msg key not renamed")
+	}
+}
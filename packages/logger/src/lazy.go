@@ -0,0 +1,26 @@
+package logger
+
+import "log/slog"
+
+// Lazy returns a value that calls fn only when a handler formats the record,
+// for attributes that are expensive to compute:
+//
+//	logger.Debug(ctx, "cache state", "entries", logger.Lazy(func() any {
+//		return cache.Dump()
+//	}))
+//
+// The level check happens first, so fn never runs for filtered records. The
+// arguments to the logging call are still evaluated, so capture the work in
+// fn rather than passing its result. fn may run once per output with
+// WithHandlers, and on the background goroutine with WithAsync, so it must
+// be safe to call concurrently with the caller.
+func Lazy(fn func() any) slog.Value {
+	return slog.AnyValue(lazyValue(fn))
+}
+
+// lazyValue defers fn to slog's LogValuer resolution.
+type lazyValue func() any
+
+func (f lazyValue) LogValue() slog.Value {
+	return slog.AnyValue(f())
+}
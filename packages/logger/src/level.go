@@ -0,0 +1,106 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+// LevelTrace is a level below Debug for very verbose diagnostics. It is
+// rendered as "TRACE".
+const LevelTrace = slog.Level(-8)
+
+// level is the shared threshold for the default logger. It is a slog.LevelVar
+// so it can be changed at runtime and every derived logger sees the update.
+var level = new(slog.LevelVar)
+
+// SetLevel changes the minimum level of the default logger. It is safe to call
+// concurrently and takes effect immediately for every logger derived from it,
+// including those returned by Ctx, Named or With before the call. A logger
+// built with New follows it only with WithSharedLevel.
+func SetLevel(l slog.Level) {
+	level.Set(l)
+}
+
+// WithSharedLevel ties the minimum level of a logger built with New to
+// SetLevel, as for the default logger built on first use. Use it when
+// replacing the default with SetDefault so that runtime level changes keep
+// working.
+func WithSharedLevel() Option {
+	return WithLevel(level)
+}
+
+// GetLevel returns the current minimum level of the default logger.
+func GetLevel() slog.Level {
+	return level.Level()
+}
+
+// Enabled reports whether Ctx(ctx) would emit a record at level, using the
+// logger stored with WithLogger if any, so callers can skip building
+// expensive arguments:
+//
+//	if logger.Enabled(ctx, slog.LevelDebug) {
+//		logger.Debug(ctx, "state", "dump", expensiveDump())
+//	}
+func Enabled(ctx context.Context, level slog.Level) bool {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	// Ctx's attributes do not change the answer, so skip building them
+	return baseLogger(ctx).Enabled(ctx, level)
+}
+
+// ParseLevel converts a level name ("trace", "debug", "info", "warn",
+// "error", with an optional "+N"/"-N" offset) or a numeric slog level ("-4",
+// "0", "8") into a slog.Level. Names are case-insensitive.
+func ParseLevel(s string) (slog.Level, error) {
+	s = strings.TrimSpace(s)
+	if n, err := strconv.Atoi(s); err == nil {
+		return slog.Level(n), nil
+	}
+
+	// slog has no trace name; parse it as an offset from debug
+	if len(s) >= 5 && strings.EqualFold(s[:5], "trace") {
+		l, err := ParseLevel("debug" + s[5:])
+		if err != nil {
+			return slog.LevelInfo, fmt.Errorf("logger: invalid level %q", s)
+		}
+		return l + (LevelTrace - slog.LevelDebug), nil
+	}
+
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(s)); err != nil {
+		return slog.LevelInfo, fmt.Errorf("logger: invalid level %q", s)
+	}
+
+	return l, nil
+}
+
+// LevelName returns the name used for l in output. It matches
+// slog.Level.String except below Debug, where levels are named relative to
+// LevelTrace ("TRACE", "TRACE+1", "TRACE-2").
+func LevelName(l slog.Level) string {
+	if l >= slog.LevelDebug {
+		return l.String()
+	}
+
+	if d := l - LevelTrace; d != 0 {
+		return fmt.Sprintf("TRACE%+d", d)
+	}
+
+	return "TRACE"
+}
+
+// replaceLevel renders the built-in level attribute with LevelName.
+func replaceLevel(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) == 0 && a.Key == slog.LevelKey {
+		if l, ok := a.Value.Any().(slog.Level); ok && l < slog.LevelDebug {
+			a.Value = slog.StringValue(LevelName(l))
+		}
+	}
+
+	return a
+}
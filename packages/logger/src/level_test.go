@@ -0,0 +1,72 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+)
+
+func TestSetLevelAppliesToExistingLoggers(t *testing.T) {
+	prev := GetLevel()
+	t.Cleanup(func() { SetLevel(prev) })
+	SetLevel(slog.LevelInfo)
+
+	out := captureDefault(t, WithSharedLevel())
+	ctx := WithCorrelation(context.Background(), "abc")
+	loggers := map[string]*slog.Logger{
+		"Default": Default(),
+		"Ctx":     Ctx(ctx),
+		"Named":   Named("db"),
+		"With":    Default().With("k", "v"),
+	}
+
+	for _, l := range loggers {
+		l.Debug("before")
+	}
+	SetLevel(slog.LevelDebug)
+	for name, l := range loggers {
+		l.Debug(name)
+	}
+
+	got := map[string]bool{}
+	for _, r := range records(t, out.String()) {
+		got[r["msg"].(string)] = true
+	}
+	if got["before"] {
+		t.Error("debug record written at info level")
+	}
+	for name := range loggers {
+		if !got[name] {
+			t.Errorf("%s logger obtained before SetLevel dropped its debug record", name)
+		}
+	}
+}
+
+func TestSharedLevelOnlyWithOption(t *testing.T) {
+	prev := GetLevel()
+	t.Cleanup(func() { SetLevel(prev) })
+	SetLevel(slog.LevelDebug)
+
+	ctx := context.Background()
+	if New(WithWriter(&syncBuffer{})).Enabled(ctx, slog.LevelDebug) {
+		t.Error("New without WithSharedLevel follows SetLevel")
+	}
+	if !New(WithWriter(&syncBuffer{}), WithSharedLevel()).Enabled(ctx, slog.LevelDebug) {
+		t.Error("New with WithSharedLevel ignores SetLevel")
+	}
+}
+
+func TestEnabledUsesContextLogger(t *testing.T) {
+	captureDefault(t, WithLevel(slog.LevelInfo))
+	ctx := WithLogger(context.Background(), New(WithWriter(&syncBuffer{}), WithLevel(slog.LevelDebug)))
+
+	if !Enabled(ctx, slog.LevelDebug) {
+		t.Error("Enabled ignores the context logger")
+	}
+	if Enabled(context.Background(), slog.LevelDebug) {
+		t.Error("Enabled without a context logger ignores the default")
+	}
+	if got, want := Enabled(ctx, slog.LevelDebug), Ctx(ctx).Enabled(ctx, slog.LevelDebug); got != want {
+		t.Errorf("Enabled = %v, Ctx(ctx).Enabled = %v", got, want)
+	}
+}
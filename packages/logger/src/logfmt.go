@@ -0,0 +1,234 @@
+package logger
+
+import (
+	"context"
+	"encoding"
+	"fmt"
+	"io"
+	"log/slog"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// logfmtHandler writes records as logfmt lines: key=value pairs separated by
+// spaces, with values quoted when they contain spaces, quotes, '=' or control
+// characters. Group members are flattened to group.key.
+type logfmtHandler struct {
+	w    io.Writer
+	mu   *sync.Mutex
+	opts slog.HandlerOptions
+
+	// attrs is the pre-rendered output of WithAttrs calls.
+	attrs []byte
+	// groups are the open groups from WithGroup, and prefix their joined form.
+	groups []string
+	prefix string
+}
+
+// NewLogfmtHandler returns a handler writing logfmt to w. A nil opts uses the
+// defaults, as for slog.NewTextHandler.
+func NewLogfmtHandler(w io.Writer, opts *slog.HandlerOptions) slog.Handler {
+	h := &logfmtHandler{w: w, mu: &sync.Mutex{}}
+	if opts != nil {
+		h.opts = *opts
+	}
+
+	return h
+}
+
+func (h *logfmtHandler) Enabled(_ context.Context, level slog.Level) bool {
+	min := slog.LevelInfo
+	if h.opts.Level != nil {
+		min = h.opts.Level.Level()
+	}
+
+	return level >= min
+}
+
+func (h *logfmtHandler) Handle(_ context.Context, r slog.Record) error {
+	buf := make([]byte, 0, 256)
+
+	if !r.Time.IsZero() {
+		buf = h.appendBuiltin(buf, slog.Time(slog.TimeKey, r.Time))
+	}
+	buf = h.appendBuiltin(buf, slog.Any(slog.LevelKey, r.Level))
+	if h.opts.AddSource && r.PC != 0 {
+		buf = h.appendBuiltin(buf, slog.Any(slog.SourceKey, recordSource(r)))
+	}
+	buf = h.appendBuiltin(buf, slog.String(slog.MessageKey, r.Message))
+
+	buf = append(buf, h.attrs...)
+	r.Attrs(func(a slog.Attr) bool {
+		buf = h.appendAttr(buf, h.prefix, h.groups, a)
+		return true
+	})
+
+	// Drop the leading separator and terminate the line
+	if len(buf) > 0 && buf[0] == ' ' {
+		buf = buf[1:]
+	}
+	buf = append(buf, '\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := h.w.Write(buf)
+
+	return err
+}
+
+func (h *logfmtHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+
+	h2 := *h
+	h2.attrs = append([]byte(nil), h.attrs...)
+	for _, a := range attrs {
+		h2.attrs = h.appendAttr(h2.attrs, h.prefix, h.groups, a)
+	}
+
+	return &h2
+}
+
+func (h *logfmtHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	h2 := *h
+	h2.groups = append(append([]string(nil), h.groups...), name)
+	h2.prefix = h.prefix + name + "."
+
+	return &h2
+}
+
+// appendBuiltin writes one of the record's built-in attributes.
+func (h *logfmtHandler) appendBuiltin(buf []byte, a slog.Attr) []byte {
+	if rep := h.opts.ReplaceAttr; rep != nil {
+		a = rep(nil, a)
+	}
+	if a.Key == "" {
+		return buf
+	}
+
+	switch v := a.Value.Any().(type) {
+	case time.Time:
+		return appendPair(buf, a.Key, v.Format("2006-01-02T15:04:05.000Z07:00"))
+	case slog.Level:
+		return appendPair(buf, a.Key, LevelName(v))
+	case *slog.Source:
+		return appendPair(buf, a.Key, v.File+":"+strconv.Itoa(v.Line))
+	}
+
+	return appendPair(buf, a.Key, logfmtValue(a.Value))
+}
+
+// appendAttr writes a user attribute, flattening groups under prefix.
+func (h *logfmtHandler) appendAttr(buf []byte, prefix string, groups []string, a slog.Attr) []byte {
+	a.Value = a.Value.Resolve()
+	if a.Value.Kind() != slog.KindGroup && h.opts.ReplaceAttr != nil {
+		a = h.opts.ReplaceAttr(groups, a)
+		a.Value = a.Value.Resolve()
+	}
+	if a.Equal(slog.Attr{}) {
+		return buf
+	}
+
+	if a.Value.Kind() == slog.KindGroup {
+		members := a.Value.Group()
+		if len(members) == 0 {
+			return buf
+		}
+		// A group with an empty key is inlined into its parent
+		if a.Key != "" {
+			prefix += a.Key + "."
+			groups = append(groups[:len(groups):len(groups)], a.Key)
+		}
+		for _, ga := range members {
+			buf = h.appendAttr(buf, prefix, groups, ga)
+		}
+		return buf
+	}
+
+	return appendPair(buf, prefix+a.Key, logfmtValue(a.Value))
+}
+
+// appendPair writes " key=value", quoting either side if needed.
+func appendPair(buf []byte, key, value string) []byte {
+	buf = append(buf, ' ')
+	buf = appendLogfmtString(buf, key)
+	buf = append(buf, '=')
+
+	return appendLogfmtString(buf, value)
+}
+
+// appendLogfmtString writes s bare when it is safe, quoted otherwise.
+func appendLogfmtString(buf []byte, s string) []byte {
+	if needsQuoting(s) {
+		return strconv.AppendQuote(buf, s)
+	}
+
+	return append(buf, s...)
+}
+
+// needsQuoting reports whether s is empty or contains characters that would
+// break a key=value token.
+func needsQuoting(s string) bool {
+	if s == "" {
+		return true
+	}
+
+	for i := 0; i < len(s); {
+		b := s[i]
+		if b < utf8.RuneSelf {
+			if b <= ' ' || b == '=' || b == '"' || b == '\\' || b == 0x7f {
+				return true
+			}
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError || unicode.IsSpace(r) || !unicode.IsPrint(r) {
+			return true
+		}
+		i += size
+	}
+
+	return false
+}
+
+// logfmtValue renders a resolved, non-group value as text.
+func logfmtValue(v slog.Value) string {
+	switch v.Kind() {
+	case slog.KindString:
+		return v.String()
+	case slog.KindTime:
+		return v.Time().Format(time.RFC3339Nano)
+	case slog.KindAny:
+		switch x := v.Any().(type) {
+		case error:
+			return x.Error()
+		case encoding.TextMarshaler:
+			if b, err := x.MarshalText(); err == nil {
+				return string(b)
+			}
+		case []byte:
+			return string(x)
+		}
+		return fmt.Sprint(v.Any())
+	default:
+		return v.String()
+	}
+}
+
+// recordSource resolves the record's program counter to a file and line.
+func recordSource(r slog.Record) *slog.Source {
+	fs := runtime.CallersFrames([]uintptr{r.PC})
+	f, _ := fs.Next()
+
+	return &slog.Source{Function: f.Function, File: f.File, Line: f.Line}
+}
@@ -0,0 +1,104 @@
+package logger
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+)
+
+// newTestLogfmt returns a logfmt logger without the time, for stable lines.
+func newTestLogfmt(buf *bytes.Buffer) *slog.Logger {
+	return slog.New(NewLogfmtHandler(buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+}
+
+func TestLogfmtEscaping(t *testing.T) {
+	tests := []struct {
+		name  string
+		key   string
+		value any
+		want  string
+	}{
+		{"bare", "k", "plain", `k=plain`},
+		{"space", "k", "a b", `k="a b"`},
+		{"tab", "k", "a\tb", `k="a\tb"`},
+		{"quote", "k", `say "hi"`, `k="say \"hi\""`},
+		{"backslash", "k", `C:\dir`, `k="C:\\dir"`},
+		{"newline", "k", "one\ntwo", `k="one\ntwo"`},
+		{"equals", "k", "a=b", `k="a=b"`},
+		{"empty", "k", "", `k=""`},
+		{"nul", "k", "\x00", `k="\x00"`},
+		{"del", "k", "\x7f", `k="\x7f"`},
+		{"zero width", "k", "a\u200bb", `k="a\u200bb"`},
+		{"unicode space", "k", "a\u00a0b", `k="a\u00a0b"`},
+		{"invalid utf8", "k", "\xff", `k="\xff"`},
+		{"printable unicode", "k", "héllo", `k=héllo`},
+		{"key with space", "my key", "v", `"my key"=v`},
+		{"key with equals", "a=b", "v", `"a=b"=v`},
+		{"number", "n", 42, `n=42`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			newTestLogfmt(&buf).Info("m", tt.key, tt.value)
+
+			if got, want := buf.String(), "level=INFO msg=m "+tt.want+"\n"; got != want {
+				t.Errorf("got  %q\nwant %q", got, want)
+			}
+		})
+	}
+}
+
+func TestLogfmtGroups(t *testing.T) {
+	tests := []struct {
+		name string
+		log  func(*slog.Logger)
+		want string
+	}{
+		{"inline group", func(l *slog.Logger) {
+			l.Info("m", slog.Group("http", "method", "GET", slog.Group("req", "id", 1)))
+		}, `http.method=GET http.req.id=1`},
+		{"WithGroup", func(l *slog.Logger) {
+			l.WithGroup("req").With("id", 7).Info("m", "path", "/a b")
+		}, `req.id=7 req.path="/a b"`},
+		{"nested WithGroup", func(l *slog.Logger) {
+			l.With("top", 1).WithGroup("a").WithGroup("b").Info("m", "k", "v")
+		}, `top=1 a.b.k=v`},
+		{"empty key group inlined", func(l *slog.Logger) {
+			l.Info("m", slog.Group("", "k", "v"))
+		}, `k=v`},
+		{"empty group omitted", func(l *slog.Logger) {
+			l.Info("m", slog.Group("g"), "k", "v")
+		}, `k=v`},
+		{"quoted group key", func(l *slog.Logger) {
+			l.WithGroup("my group").Info("m", "k", "v")
+		}, `"my group.k"=v`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			tt.log(newTestLogfmt(&buf))
+
+			if got, want := buf.String(), "level=INFO msg=m "+tt.want+"\n"; got != want {
+				t.Errorf("got  %q\nwant %q", got, want)
+			}
+		})
+	}
+}
+
+func TestLogfmtMessageQuoted(t *testing.T) {
+	var buf bytes.Buffer
+	newTestLogfmt(&buf).Warn("disk almost full")
+
+	if got, want := buf.String(), "level=WARN msg=\"disk almost full\"\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
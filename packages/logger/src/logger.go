@@ -4,6 +4,14 @@ import (
 	"context"
 	"log/slog"
 	"os"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
 type contextKey string
@@ -12,52 +20,389 @@ const (
 	TraceIDKey contextKey = "trace_id"
 )
 
-var defaultLogger *slog.Logger
+// defaultLogger is read on every log call, so it is swapped atomically.
+// defaultMu orders the writers, so that it and slog's default stay in step.
+var (
+	defaultLogger atomic.Pointer[slog.Logger]
+	defaultMu     sync.Mutex
+)
+
+// envOpts are the LOG_* settings read at init, applied when the default
+// logger is built.
+var envOpts []Option
+
+// ExitFunc terminates the process after Fatal has logged. Tests can replace it
+// to observe the exit instead of stopping the test binary.
+var ExitFunc = os.Exit
+
+// ExitCode is the status Fatal passes to ExitFunc.
+var ExitCode = 1
+
+// Flusher is implemented by handlers that buffer records and must be drained
+// before the process exits.
+type Flusher interface {
+	Flush() error
+}
 
 func init() {
-	// Default to JSON for production-ready logs
-	opts := &slog.HandlerOptions{
-		Level: slog.LevelInfo,
+	// Only read the environment here; the logger itself is built on first
+	// use, so that Configure can still replace it before anything is written
+	loadEnv()
+	slog.SetDefault(slog.New(lazyHandler{}))
+}
+
+// loadEnv reads the LOG_* settings into envOpts and reports invalid ones,
+// surfacing misconfiguration without failing init.
+func loadEnv() {
+	var errs []error
+	envOpts, errs = envOptions()
+
+	for _, err := range errs {
+		reportError(err)
+	}
+}
+
+// Default returns the logger used by Ctx and the package helpers. Unless
+// Configure or SetDefault ran first, the first call builds it from the
+// environment: JSON to stdout for production-ready logs, adjusted by
+// LOG_LEVEL, LOG_FORMAT and LOG_SOURCE without code changes.
+func Default() *slog.Logger {
+	if l := defaultLogger.Load(); l != nil {
+		return l
+	}
+
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+
+	return buildDefault()
+}
+
+// buildDefault returns the default logger, building it from the environment
+// if there is none yet; the caller holds defaultMu.
+func buildDefault() *slog.Logger {
+	// Another goroutine may have won the race
+	if l := defaultLogger.Load(); l != nil {
+		return l
+	}
+
+	l := New(append([]Option{WithLevel(level)}, envOpts...)...)
+	storeDefault(l)
+
+	return l
+}
+
+// SetDefault replaces the logger used by Ctx and the package helpers, and
+// slog's default. It is safe to call while other goroutines log; loggers
+// already derived from the old default, e.g. by Named, keep using it.
+func SetDefault(l *slog.Logger) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+
+	storeDefault(l)
+}
+
+// storeDefault installs l and returns the logger it replaced, if any; the
+// caller holds defaultMu.
+func storeDefault(l *slog.Logger) *slog.Logger {
+	// slog.Default() before the first log stands for this package's default,
+	// which would then call itself; keep or build the real one instead
+	if _, ok := l.Handler().(lazyHandler); ok {
+		l = buildDefault()
+	}
+
+	prev := defaultLogger.Swap(l)
+	slog.SetDefault(l)
+
+	return prev
+}
+
+// SetHandler makes h the handler of the default logger, for example to start
+// mirroring to a debug file at runtime, and returns the previous handler so
+// it can be restored. Attributes added by Init are not carried over; h gets
+// exactly the records logged from then on. It is safe for concurrent use.
+func SetHandler(h slog.Handler) slog.Handler {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+
+	// Build the environment default first, so there is one to return
+	prev := buildDefault()
+	storeDefault(slog.New(h))
+
+	return prev.Handler()
+}
+
+// lazyHandler stands in as slog's default until the logger is built, which
+// its first use does.
+type lazyHandler struct{}
+
+func (lazyHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return Default().Handler().Enabled(ctx, level)
+}
+
+func (lazyHandler) Handle(ctx context.Context, r slog.Record) error {
+	return Default().Handler().Handle(ctx, r)
+}
+
+func (lazyHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return Default().Handler().WithAttrs(attrs)
+}
+
+func (lazyHandler) WithGroup(name string) slog.Handler {
+	return Default().Handler().WithGroup(name)
+}
+
+const loggerKey contextKey = "logger"
+
+// WithLogger returns a context carrying l, which Ctx and the package helpers
+// then use instead of the default logger for that part of the call tree.
+func WithLogger(ctx context.Context, l *slog.Logger) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
 	}
 
-	// If in development mode, we could use TextHandler,
-	// but JSON is safer for the monorepo standard.
-	handler := slog.NewJSONHandler(os.Stdout, opts)
-	defaultLogger = slog.New(handler)
-	slog.SetDefault(defaultLogger)
+	return context.WithValue(ctx, loggerKey, l)
 }
 
-// Ctx returns a logger that includes the trace_id from the context if present.
+// Ctx returns a logger that includes the trace_id and any WithFields
+// attributes from the context if present. When the context carries an active
+// OpenTelemetry span, its span_id is attached too, and its trace ID is used
+// unless one was set explicitly with WithCorrelation. The logger is derived
+// from the one stored with WithLogger, or else the default logger, and writes
+// into the context's Buffer, if any.
 func Ctx(ctx context.Context) *slog.Logger {
 	if ctx == nil {
-		return defaultLogger
+		return Default()
+	}
+
+	l := ctxLogger(ctx)
+	if b := bufferFromContext(ctx); b != nil {
+		l = slog.New(&bufferHandler{inner: l.Handler(), buf: b})
+	}
+
+	return l
+}
+
+// baseLogger returns the logger stored with WithLogger, or the default.
+func baseLogger(ctx context.Context) *slog.Logger {
+	if l, ok := ctx.Value(loggerKey).(*slog.Logger); ok && l != nil {
+		return l
 	}
 
-	if traceID, ok := ctx.Value(TraceIDKey).(string); ok {
-		return defaultLogger.With(slog.String("trace_id", traceID))
+	return Default()
+}
+
+// ctxLogger is Ctx without the Buffer.
+func ctxLogger(ctx context.Context) *slog.Logger {
+	base := baseLogger(ctx)
+
+	fields := fieldsFromContext(ctx)
+	traceID, hasTrace := TraceIDFromContext(ctx)
+	span := trace.SpanContextFromContext(ctx)
+	if !hasTrace && !span.IsValid() && len(fields) == 0 {
+		return base
+	}
+
+	// An explicit WithCorrelation ID wins over the span's trace ID
+	if !hasTrace && span.HasTraceID() {
+		traceID, hasTrace = span.TraceID().String(), true
+	}
+
+	args := make([]any, 0, len(fields)+2)
+	if hasTrace {
+		args = append(args, slog.String("trace_id", traceID))
+	}
+	if span.HasSpanID() {
+		args = append(args, slog.Any("span_id", spanIDValue{span: span}))
 	}
+	for _, f := range fields {
+		args = append(args, f)
+	}
+
+	return base.With(args...)
+}
+
+// WithGroup returns a logger for ctx whose subsequent attributes are nested
+// under name, while trace_id, span_id and WithFields attributes stay at the
+// top level. Related fields can also be grouped per call with slog.Group:
+//
+//	logger.Info(ctx, "request", slog.Group("http", "method", m, "status", s))
+func WithGroup(ctx context.Context, name string) *slog.Logger {
+	return Ctx(ctx).WithGroup(name)
+}
+
+// ComponentKey is the attribute key set by Named and NamedCtx.
+const ComponentKey = "component"
+
+// Named returns the default logger tagged with a component attribute. It is
+// derived from the logger current at call time, so resolve it after
+// SetDefault rather than in a package-level var.
+func Named(component string) *slog.Logger {
+	return Default().With(slog.String(ComponentKey, component))
+}
 
-	return defaultLogger
+// NamedCtx is Named with the trace_id, span_id and WithFields attributes of
+// ctx attached, as with Ctx.
+func NamedCtx(ctx context.Context, component string) *slog.Logger {
+	return Ctx(ctx).With(slog.String(ComponentKey, component))
 }
 
-// WithCorrelation adds a trace ID to the context
+// WithCorrelation adds a trace ID to the context, after trimming and the
+// checks set with SetTraceIDPolicy. An empty or rejected ID leaves ctx as is.
 func WithCorrelation(ctx context.Context, traceID string) context.Context {
+	traceID, ok := normalizeTraceID(traceID)
+	if !ok {
+		return ctx
+	}
+
 	return context.WithValue(ctx, TraceIDKey, traceID)
 }
 
+// WithCorrelations attaches a set of correlation IDs in one call, such as
+// trace_id, message_id and queue for a message consumer. The "trace_id"
+// entry is stored as with WithCorrelation, but only when ctx has no trace ID
+// yet; the others become WithFields attributes, in key order. Empty values
+// are skipped.
+func WithCorrelations(ctx context.Context, ids map[string]string) context.Context {
+	return withCorrelations(ctx, ids, false)
+}
+
+// ReplaceCorrelations is WithCorrelations, except that a "trace_id" entry
+// replaces the trace ID already on ctx.
+func ReplaceCorrelations(ctx context.Context, ids map[string]string) context.Context {
+	return withCorrelations(ctx, ids, true)
+}
+
+func withCorrelations(ctx context.Context, ids map[string]string, replace bool) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	keys := make([]string, 0, len(ids))
+	for k, v := range ids {
+		if k == string(TraceIDKey) {
+			if _, ok := TraceIDFromContext(ctx); replace || !ok {
+				ctx = WithCorrelation(ctx, v)
+			}
+			continue
+		}
+		if strings.TrimSpace(v) != "" {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+
+	args := make([]any, 0, len(keys))
+	for _, k := range keys {
+		args = append(args, slog.String(k, ids[k]))
+	}
+
+	return WithFields(ctx, args...)
+}
+
+// TraceIDFromContext returns the trace ID stored with WithCorrelation and
+// whether a non-empty one was present.
+func TraceIDFromContext(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+
+	traceID, _ := ctx.Value(TraceIDKey).(string)
+	return traceID, traceID != ""
+}
+
 // Public helpers for quick logging
 func Info(ctx context.Context, msg string, args ...any) {
-	Ctx(ctx).Info(msg, args...)
+	emit(ctx, slog.LevelInfo, msg, args...)
 }
 
 func Error(ctx context.Context, msg string, args ...any) {
-	Ctx(ctx).Error(msg, args...)
+	emit(ctx, slog.LevelError, msg, args...)
 }
 
 func Warn(ctx context.Context, msg string, args ...any) {
-	Ctx(ctx).Warn(msg, args...)
+	emit(ctx, slog.LevelWarn, msg, args...)
 }
 
 func Debug(ctx context.Context, msg string, args ...any) {
-	Ctx(ctx).Debug(msg, args...)
+	emit(ctx, slog.LevelDebug, msg, args...)
+}
+
+func Trace(ctx context.Context, msg string, args ...any) {
+	emit(ctx, LevelTrace, msg, args...)
+}
+
+// Attr variants of the helpers avoid the []any allocation of the variadic
+// key-value form on hot paths
+func InfoAttrs(ctx context.Context, msg string, attrs ...slog.Attr) {
+	emitAttrs(ctx, slog.LevelInfo, msg, attrs...)
+}
+
+func ErrorAttrs(ctx context.Context, msg string, attrs ...slog.Attr) {
+	emitAttrs(ctx, slog.LevelError, msg, attrs...)
+}
+
+func WarnAttrs(ctx context.Context, msg string, attrs ...slog.Attr) {
+	emitAttrs(ctx, slog.LevelWarn, msg, attrs...)
+}
+
+func DebugAttrs(ctx context.Context, msg string, attrs ...slog.Attr) {
+	emitAttrs(ctx, slog.LevelDebug, msg, attrs...)
+}
+
+func TraceAttrs(ctx context.Context, msg string, attrs ...slog.Attr) {
+	emitAttrs(ctx, LevelTrace, msg, attrs...)
+}
+
+// Fatal logs at error level, flushes with Sync and exits the process with
+// ExitCode.
+func Fatal(ctx context.Context, msg string, args ...any) {
+	emit(ctx, slog.LevelError, msg, args...)
+	_ = Sync()
+	ExitFunc(ExitCode)
+}
+
+// Panic logs at error level, flushes with Sync and panics with msg.
+func Panic(ctx context.Context, msg string, args ...any) {
+	emit(ctx, slog.LevelError, msg, args...)
+	_ = Sync()
+	panic(msg)
+}
+
+// emit logs through Ctx(ctx) while attributing the record to the caller of
+// the exported helper, so AddSource points at user code instead of this file.
+func emit(ctx context.Context, level slog.Level, msg string, args ...any) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	l := Ctx(ctx)
+	if !l.Enabled(ctx, level) {
+		return
+	}
+
+	var pcs [1]uintptr
+	runtime.Callers(3, pcs[:]) // skip [Callers, emit, helper]
+
+	r := slog.NewRecord(time.Now(), level, msg, pcs[0])
+	r.Add(args...)
+	_ = l.Handler().Handle(ctx, r)
+}
+
+// emitAttrs is emit for slog.Attr arguments, following Logger.LogAttrs.
+func emitAttrs(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	l := Ctx(ctx)
+	if !l.Enabled(ctx, level) {
+		return
+	}
+
+	var pcs [1]uintptr
+	runtime.Callers(3, pcs[:]) // skip [Callers, emitAttrs, helper]
+
+	r := slog.NewRecord(time.Now(), level, msg, pcs[0])
+	r.AddAttrs(attrs...)
+	_ = l.Handler().Handle(ctx, r)
 }
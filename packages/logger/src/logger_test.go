@@ -0,0 +1,124 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// captureDefault makes the default logger write JSON to the returned buffer
+// for the rest of the test.
+func captureDefault(t testing.TB, opts ...Option) *syncBuffer {
+	t.Helper()
+
+	prev, prevSlog := defaultLogger.Load(), slog.Default()
+	t.Cleanup(func() {
+		defaultLogger.Store(prev)
+		slog.SetDefault(prevSlog)
+	})
+
+	out := &syncBuffer{}
+	SetDefault(New(append([]Option{WithWriter(out)}, opts...)...))
+
+	return out
+}
+
+func TestSourceIsCaller(t *testing.T) {
+	tests := []struct {
+		name string
+		log  func(context.Context)
+	}{
+		{"emit", func(ctx context.Context) { Info(ctx, "msg") }},
+		{"emitAttrs", func(ctx context.Context) { InfoAttrs(ctx, "msg", slog.Int("n", 1)) }},
+		{"ErrorWithStack", func(ctx context.Context) { ErrorWithStack(ctx, "msg", errors.New("boom")) }},
+		{"logPanic", func(ctx context.Context) {
+			defer Recover(ctx)
+			panic("boom")
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := captureDefault(t, WithAddSource(true))
+			tt.log(context.Background())
+
+			recs := records(t, out.String())
+			if len(recs) != 1 {
+				t.Fatalf("got %d records, want 1", len(recs))
+			}
+			src, _ := recs[0][slog.SourceKey].(map[string]any)
+			file, _ := src["file"].(string)
+			fn, _ := src["function"].(string)
+			if filepath.Base(file) != "logger_test.go" || !strings.Contains(fn, "TestSourceIsCaller") {
+				t.Errorf("source = %s in %s, want the test's call site", fn, file)
+			}
+		})
+	}
+}
+
+func TestSetHandlerWhileLogging(t *testing.T) {
+	initial := captureDefault(t)
+
+	outs := []*syncBuffer{{}, {}}
+	const goroutines, perGoroutine = 8, 200
+	var wg sync.WaitGroup
+	for range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range perGoroutine {
+				Info(context.Background(), "tick")
+			}
+		}()
+	}
+	for i := range 100 {
+		SetHandler(slog.NewJSONHandler(outs[i%2], nil))
+	}
+	wg.Wait()
+
+	// Every record lands whole in exactly one of the outputs
+	total := 0
+	for _, out := range append(outs, initial) {
+		total += len(records(t, out.String()))
+	}
+	if total != goroutines*perGoroutine {
+		t.Errorf("wrote %d records, want %d", total, goroutines*perGoroutine)
+	}
+}
+
+func TestSetHandlerReturnsPrevious(t *testing.T) {
+	captureDefault(t)
+
+	first := slog.NewJSONHandler(&syncBuffer{}, nil)
+	SetHandler(first)
+	if prev := SetHandler(slog.NewJSONHandler(&syncBuffer{}, nil)); prev != first {
+		t.Errorf("SetHandler returned %v, want the previous handler", prev)
+	}
+}
+
+// The varargs helpers box every value into the ...any slice; the Attrs
+// helpers avoid that. Compare with -benchmem.
+func BenchmarkInfo(b *testing.B) {
+	captureDefault(b, WithWriter(io.Discard))
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	for i := range b.N {
+		Info(ctx, "request handled", "status", 200+i%300, "bytes", int64(i), "elapsed", float64(i))
+	}
+}
+
+func BenchmarkInfoAttrs(b *testing.B) {
+	captureDefault(b, WithWriter(io.Discard))
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	for i := range b.N {
+		InfoAttrs(ctx, "request handled", slog.Int("status", 200+i%300), slog.Int64("bytes", int64(i)), slog.Float64("elapsed", float64(i)))
+	}
+}
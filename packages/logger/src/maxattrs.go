@@ -0,0 +1,139 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+)
+
+// AttrsTruncatedKey holds the number of attributes dropped by WithMaxAttrs or
+// WithMaxGroupAttrs.
+const AttrsTruncatedKey = "attrs_truncated"
+
+// WithMaxAttrs keeps at most n top-level attributes per record, counting
+// those added with With or Ctx, so that a bad call site cannot explode a
+// record into thousands of fields. The first n are kept and the number
+// dropped is recorded under attrs_truncated.
+func WithMaxAttrs(n int) Option {
+	return func(c *config) {
+		c.maxAttrs = n
+	}
+}
+
+// WithMaxGroupAttrs applies the same limit to the members of every group, at
+// any depth, with attrs_truncated added inside the group.
+func WithMaxGroupAttrs(n int) Option {
+	return func(c *config) {
+		c.maxGroupAttrs = n
+	}
+}
+
+// maxAttrsHandler enforces the attribute limits. limit applies at the
+// current level, the top level or the innermost WithGroup, of which count
+// attributes were already added and dropped discarded. A limit of zero
+// means no limit.
+type maxAttrsHandler struct {
+	inner      slog.Handler
+	limit      int
+	groupLimit int
+	count      int
+	dropped    int
+}
+
+func newMaxAttrsHandler(h slog.Handler, limit, groupLimit int) slog.Handler {
+	if limit <= 0 && groupLimit <= 0 {
+		return h
+	}
+
+	return &maxAttrsHandler{inner: h, limit: max(limit, 0), groupLimit: max(groupLimit, 0)}
+}
+
+func (h *maxAttrsHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+func (h *maxAttrsHandler) Handle(ctx context.Context, r slog.Record) error {
+	fits := h.limit == 0 || h.count+r.NumAttrs() <= h.limit
+	if fits && h.groupLimit == 0 && h.dropped == 0 {
+		return h.inner.Handle(ctx, r)
+	}
+
+	attrs := make([]slog.Attr, 0, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+	attrs, dropped := h.cap(attrs, h.count, h.limit)
+
+	out := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	out.AddAttrs(attrs...)
+	if total := h.dropped + dropped; total > 0 {
+		out.AddAttrs(slog.Int(AttrsTruncatedKey, total))
+	}
+
+	return h.inner.Handle(ctx, out)
+}
+
+func (h *maxAttrsHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	kept, dropped := h.cap(attrs, h.count, h.limit)
+
+	h2 := *h
+	h2.inner = h.inner.WithAttrs(kept)
+	h2.count += len(kept)
+	h2.dropped += dropped
+
+	return &h2
+}
+
+func (h *maxAttrsHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	// What was dropped so far is reported at the level it was dropped from
+	inner := h.inner
+	if h.dropped > 0 {
+		inner = inner.WithAttrs([]slog.Attr{slog.Int(AttrsTruncatedKey, h.dropped)})
+	}
+
+	return &maxAttrsHandler{
+		inner:      inner.WithGroup(name),
+		limit:      h.groupLimit,
+		groupLimit: h.groupLimit,
+	}
+}
+
+// cap keeps the attributes that fit after count existing ones, limiting
+// group members on the way, and returns them with the number dropped.
+func (h *maxAttrsHandler) cap(attrs []slog.Attr, count, limit int) ([]slog.Attr, int) {
+	dropped := 0
+	if limit > 0 && count+len(attrs) > limit {
+		keep := max(limit-count, 0)
+		dropped = len(attrs) - keep
+		attrs = attrs[:keep]
+	}
+
+	if h.groupLimit > 0 {
+		capped := make([]slog.Attr, len(attrs))
+		for i, a := range attrs {
+			capped[i] = h.capGroup(a)
+		}
+		attrs = capped
+	}
+
+	return attrs, dropped
+}
+
+// capGroup applies the group limit to a and the groups nested in it.
+func (h *maxAttrsHandler) capGroup(a slog.Attr) slog.Attr {
+	a.Value = a.Value.Resolve()
+	if a.Value.Kind() != slog.KindGroup {
+		return a
+	}
+
+	members, dropped := h.cap(a.Value.Group(), 0, h.groupLimit)
+	if dropped > 0 {
+		members = append(members, slog.Int(AttrsTruncatedKey, dropped))
+	}
+
+	return slog.Attr{Key: a.Key, Value: slog.GroupValue(members...)}
+}
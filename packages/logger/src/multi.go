@@ -0,0 +1,86 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+)
+
+// multiHandler dispatches each record to every child handler that is enabled
+// for its level.
+type multiHandler struct {
+	handlers []slog.Handler
+}
+
+// NewMultiHandler returns a handler that fans records out to all handlers.
+// Each child's Enabled check is honored independently, so destinations can
+// run at different levels. Errors from children are joined.
+func NewMultiHandler(handlers ...slog.Handler) slog.Handler {
+	if len(handlers) == 1 {
+		return handlers[0]
+	}
+
+	return &multiHandler{handlers: handlers}
+}
+
+func (m *multiHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range m.handlers {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (m *multiHandler) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+	for _, h := range m.handlers {
+		if !h.Enabled(ctx, r.Level) {
+			continue
+		}
+		// Clone so one child cannot observe another's mutations
+		if err := h.Handle(ctx, r.Clone()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (m *multiHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handlers := make([]slog.Handler, len(m.handlers))
+	for i, h := range m.handlers {
+		handlers[i] = h.WithAttrs(attrs)
+	}
+
+	return &multiHandler{handlers: handlers}
+}
+
+func (m *multiHandler) WithGroup(name string) slog.Handler {
+	handlers := make([]slog.Handler, len(m.handlers))
+	for i, h := range m.handlers {
+		handlers[i] = h.WithGroup(name)
+	}
+
+	return &multiHandler{handlers: handlers}
+}
+
+// WithHandlers sends records to the given handlers in addition to the
+// logger's own output. Each filters by its own level: the logger's level,
+// and ContextWithLevel, apply to its own output only, so a child at Debug
+// gets debug records while the output stays at Info. Handlers that buffer or
+// hold resources, such as a BatchHandler, are flushed by Sync and released by
+// Close along with the logger's other resources.
+func WithHandlers(handlers ...slog.Handler) Option {
+	return func(c *config) {
+		c.handlers = append(c.handlers, handlers...)
+	}
+}
+
+// AddHandlers fans the default logger out to additional destinations. Fields
+// attached to the default logger afterwards (e.g. by Init) reach all of them.
+// Call it at startup, before the logger is shared across goroutines.
+func AddHandlers(handlers ...slog.Handler) {
+	SetDefault(slog.New(NewMultiHandler(append([]slog.Handler{Default().Handler()}, handlers...)...)))
+}
@@ -0,0 +1,64 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+)
+
+func TestWithHandlersIndependentLevels(t *testing.T) {
+	primary, verbose, strict := &syncBuffer{}, &syncBuffer{}, &syncBuffer{}
+	l := New(
+		WithWriter(primary),
+		WithLevel(slog.LevelInfo),
+		WithHandlers(
+			slog.NewJSONHandler(verbose, &slog.HandlerOptions{Level: slog.LevelDebug}),
+			slog.NewJSONHandler(strict, &slog.HandlerOptions{Level: slog.LevelError}),
+		),
+	)
+
+	l.Debug("debug")
+	l.Info("info")
+	l.Error("error")
+
+	tests := []struct {
+		name string
+		out  *syncBuffer
+		want []string
+	}{
+		{"primary", primary, []string{"info", "error"}},
+		{"verbose child", verbose, []string{"debug", "info", "error"}},
+		{"strict child", strict, []string{"error"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, r := range records(t, tt.out.String()) {
+			got = append(got, r[slog.MessageKey].(string))
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("%s got %v, want %v", tt.name, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s got %v, want %v", tt.name, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestContextLevelAppliesToOutputOnly(t *testing.T) {
+	primary, child := &syncBuffer{}, &syncBuffer{}
+	l := New(WithWriter(primary), WithHandlers(slog.NewJSONHandler(child, nil)))
+
+	ctx := ContextWithLevel(context.Background(), slog.LevelDebug)
+	l.DebugContext(ctx, "debug")
+
+	if n := len(records(t, primary.String())); n != 1 {
+		t.Errorf("output got %d records, want the overridden debug record", n)
+	}
+	if n := len(records(t, child.String())); n != 0 {
+		t.Errorf("child at Info got %d debug records", n)
+	}
+}
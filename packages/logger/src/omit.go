@@ -0,0 +1,26 @@
+package logger
+
+import "log/slog"
+
+// OmitEmpty returns an attribute for value, or the empty attribute when value
+// is its type's zero value, which every handler drops. It saves an if at call
+// sites logging optional fields:
+//
+//	logger.InfoAttrs(ctx, "login", logger.OmitEmpty("user_id", userID))
+func OmitEmpty[T comparable](key string, value T) slog.Attr {
+	var zero T
+	if value == zero {
+		return slog.Attr{}
+	}
+
+	return slog.Any(key, value)
+}
+
+// OmitEmptySlice is OmitEmpty for slices, dropping nil and empty ones.
+func OmitEmptySlice[T any](key string, value []T) slog.Attr {
+	if len(value) == 0 {
+		return slog.Attr{}
+	}
+
+	return slog.Any(key, value)
+}
@@ -0,0 +1,220 @@
+package logger
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"regexp"
+	"time"
+)
+
+// config holds the settings assembled by Options before a logger is built.
+type config struct {
+	writer    io.Writer
+	level     slog.Leveler
+	format    Format
+	addSource bool
+	attrs     []any
+
+	replacers      []func(groups []string, a slog.Attr) slog.Attr
+	time           timeConfig
+	source         sourceConfig
+	redactKeys     []string
+	redactPatterns []*regexp.Regexp
+	redactMessage  bool
+	handlers       []slog.Handler
+	stderr         *stderrRouting
+	sampling       *SamplingConfig
+	dedup          time.Duration
+	rateLimits     []rateLimit
+	async          *AsyncOptions
+	ctxErr         bool
+	order          []string
+	datadog        bool
+	color          ColorMode
+	pretty         bool
+	messageKey     string
+	levelKey       string
+	maxAttrs       int
+	maxGroupAttrs  int
+	now            func() time.Time
+	routes         []LevelRoute
+	resources      *Resources
+}
+
+// Option configures a logger created with New.
+type Option func(*config)
+
+// WithWriter sets the destination for log output. Defaults to os.Stdout.
+func WithWriter(w io.Writer) Option {
+	return func(c *config) {
+		c.writer = w
+	}
+}
+
+// WithLevel sets the minimum level. Passing a *slog.LevelVar keeps the level
+// adjustable after the logger is built. Defaults to slog.LevelInfo.
+func WithLevel(l slog.Leveler) Option {
+	return func(c *config) {
+		c.level = l
+	}
+}
+
+// WithFormat selects the output encoding. Defaults to FormatJSON.
+func WithFormat(f Format) Option {
+	return func(c *config) {
+		c.format = f
+	}
+}
+
+// WithAddSource toggles the source file and line attribute on each record.
+func WithAddSource(enabled bool) Option {
+	return func(c *config) {
+		c.addSource = enabled
+	}
+}
+
+// New builds an isolated logger. Without options it matches the default
+// logger: JSON to stdout at Info. Files and background writers opened by its
+// options are released by Close; use Open for loggers that go away sooner.
+func New(opts ...Option) *slog.Logger {
+	return build(&resources, opts)
+}
+
+// build is New, tracking what the options open in res.
+func build(res *Resources, opts []Option) *slog.Logger {
+	cfg := config{
+		writer:    os.Stdout,
+		level:     slog.LevelInfo,
+		format:    FormatJSON,
+		resources: res,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	now := cfg.now
+	if now == nil {
+		now = time.Now
+	}
+
+	// The level is the output's own; WithHandlers children keep theirs
+	var h slog.Handler = &levelHandler{inner: cfg.output(), level: cfg.level}
+	if len(cfg.handlers) > 0 {
+		for _, child := range cfg.handlers {
+			cfg.resources.addHandler(child)
+		}
+		h = NewMultiHandler(append([]slog.Handler{h}, cfg.handlers...)...)
+	}
+	if len(cfg.order) > 0 {
+		h = newOrderHandler(h, cfg.order)
+	}
+	h = &hookHandler{inner: h}
+	h = newMaxAttrsHandler(h, cfg.maxAttrs, cfg.maxGroupAttrs)
+	h = newRedactHandler(h, &cfg)
+	if cfg.sampling != nil {
+		h = newSamplingHandler(h, *cfg.sampling, now)
+	}
+	if cfg.dedup > 0 {
+		d := newDedupHandler(h, cfg.dedup, now)
+		cfg.resources.add(d.(io.Closer))
+		h = d
+	}
+	if len(cfg.rateLimits) > 0 {
+		h = newRateLimitHandler(h, cfg.rateLimits, now)
+	}
+	if cfg.async != nil {
+		a := NewAsyncHandler(h, *cfg.async)
+		cfg.resources.add(a)
+		h = a
+	}
+	// Outside the async writer, which detaches records from cancellation
+	if cfg.ctxErr {
+		h = &ctxErrHandler{inner: h}
+	}
+	// Ahead of the time windows, so they see the clock's record times
+	if cfg.now != nil {
+		h = &clockHandler{inner: h, now: cfg.now}
+	}
+	// Outside the steps that resolve values, hiding the span Ctx attaches
+	if cfg.datadog {
+		h = &datadogHandler{inner: h}
+	}
+
+	l := slog.New(h)
+	if len(cfg.attrs) > 0 {
+		l = l.With(cfg.attrs...)
+	}
+
+	return l
+}
+
+// handler builds the output slog.Handler described by the config. It accepts
+// every level; New applies the configured level above it.
+func (c *config) handler() slog.Handler {
+	opts := &slog.HandlerOptions{
+		Level:       allLevels,
+		AddSource:   c.addSource,
+		ReplaceAttr: c.replaceAttr(),
+	}
+
+	var h slog.Handler
+	switch c.format {
+	case FormatText:
+		h = slog.NewTextHandler(c.colorize(c.writer), opts)
+	case FormatLogfmt:
+		h = NewLogfmtHandler(c.writer, opts)
+	case FormatECS:
+		h = slog.NewJSONHandler(c.jsonWriter(), opts).WithAttrs([]slog.Attr{slog.String("ecs.version", ecsVersion)})
+	default:
+		h = slog.NewJSONHandler(c.jsonWriter(), opts)
+	}
+
+	if keys := c.renamedKeys(); len(keys) > 0 {
+		h = &keysHandler{inner: h, keys: keys}
+	}
+
+	return h
+}
+
+// jsonWriter is the writer for the JSON formats, indenting with WithPrettyJSON.
+func (c *config) jsonWriter() io.Writer {
+	if c.pretty {
+		return &prettyWriter{w: c.writer}
+	}
+
+	return c.writer
+}
+
+// replaceAttr chains the built-in attribute rewrites with any added by
+// options, in order, followed by the renames required by the format.
+func (c *config) replaceAttr() func([]string, slog.Attr) slog.Attr {
+	replacers := []func([]string, slog.Attr) slog.Attr{replaceLevel}
+	if rep := c.time.replacer(); rep != nil {
+		replacers = append(replacers, rep)
+	}
+	if rep := c.source.replacer(c.format); rep != nil {
+		replacers = append(replacers, rep)
+	}
+	replacers = append(replacers, c.replacers...)
+	// The renames apply to the built-ins, not user attributes named alike
+	var renames []func([]string, slog.Attr) slog.Attr
+	if c.format == FormatECS {
+		renames = append(renames, replaceECS)
+	}
+	if keys := c.keysReplacer(); keys != nil {
+		renames = append(renames, keys)
+	}
+
+	return func(groups []string, a slog.Attr) slog.Attr {
+		a, user := untag(groups, a)
+		for _, fn := range replacers {
+			a = fn(groups, a)
+		}
+		if !user {
+			for _, fn := range renames {
+				a = fn(groups, a)
+			}
+		}
+		return a
+	}
+}
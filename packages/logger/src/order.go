@@ -0,0 +1,105 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+)
+
+// CanonicalKeys is the front-matter order used by WithCanonicalOrder when no
+// keys are given.
+var CanonicalKeys = []string{"trace_id", "span_id"}
+
+// WithCanonicalOrder writes the given top-level keys, or CanonicalKeys when
+// none are given, right after time, level and msg, in that order, followed by
+// all other attributes as logged. It makes output byte-for-byte reproducible
+// for golden files regardless of where Ctx, Named or With added each key.
+func WithCanonicalOrder(keys ...string) Option {
+	return func(c *config) {
+		if len(keys) == 0 {
+			keys = CanonicalKeys
+		}
+		c.order = keys
+	}
+}
+
+// orderHandler holds back top-level WithAttrs attributes so that each record
+// can be written with the canonical keys first. Once a group is opened the
+// remaining attributes belong to it, so the held ones are passed on in order.
+type orderHandler struct {
+	inner   slog.Handler
+	keys    []string
+	pending []slog.Attr
+}
+
+func newOrderHandler(h slog.Handler, keys []string) slog.Handler {
+	return &orderHandler{inner: h, keys: keys}
+}
+
+func (h *orderHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+func (h *orderHandler) Handle(ctx context.Context, r slog.Record) error {
+	attrs := make([]slog.Attr, 0, len(h.pending)+r.NumAttrs())
+	attrs = append(attrs, h.pending...)
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+
+	out := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	out.AddAttrs(h.sort(attrs)...)
+
+	return h.inner.Handle(ctx, out)
+}
+
+func (h *orderHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+
+	return &orderHandler{
+		inner:   h.inner,
+		keys:    h.keys,
+		pending: append(slices.Clip(h.pending), attrs...),
+	}
+}
+
+func (h *orderHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	inner := h.inner
+	if len(h.pending) > 0 {
+		inner = inner.WithAttrs(h.sort(slices.Clone(h.pending)))
+	}
+
+	return inner.WithGroup(name)
+}
+
+// sort moves the canonical keys to the front in place, keeping the relative
+// order of everything else. Only the last attribute with a canonical key is
+// kept, as a JSON decoder would.
+func (h *orderHandler) sort(attrs []slog.Attr) []slog.Attr {
+	front := make([]slog.Attr, len(h.keys))
+	found := make([]bool, len(h.keys))
+	rest := attrs[:0]
+	for _, a := range attrs {
+		if i := slices.Index(h.keys, a.Key); i >= 0 {
+			front[i], found[i] = a, true
+			continue
+		}
+		rest = append(rest, a)
+	}
+
+	out := make([]slog.Attr, 0, len(front)+len(rest))
+	for i, a := range front {
+		if found[i] {
+			out = append(out, a)
+		}
+	}
+
+	return append(out, rest...)
+}
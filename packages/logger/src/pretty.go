@@ -0,0 +1,39 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+)
+
+// WithPrettyJSON indents JSON and ECS output by two spaces, one attribute per
+// line, for reading records while debugging. The output is larger and no
+// longer one record per line, which breaks most log shippers, so do not use
+// it in production.
+func WithPrettyJSON() Option {
+	return func(c *config) {
+		c.pretty = true
+	}
+}
+
+// prettyWriter re-indents each JSON record. slog's JSON handler writes one
+// whole record per Write call, and json.Indent keeps the key order.
+type prettyWriter struct {
+	w io.Writer
+}
+
+func (pw *prettyWriter) Write(p []byte) (int, error) {
+	var buf bytes.Buffer
+	buf.Grow(len(p) * 2)
+	if err := json.Indent(&buf, bytes.TrimRight(p, "\n"), "", "  "); err != nil {
+		// Not a single JSON value; pass it through unchanged
+		return pw.w.Write(p)
+	}
+	buf.WriteByte('\n')
+
+	if _, err := pw.w.Write(buf.Bytes()); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
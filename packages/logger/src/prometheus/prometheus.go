@@ -0,0 +1,67 @@
+// Package prometheus counts emitted log records by level so error-rate spikes
+// can be alerted on. It lives in its own package so the core logger does not
+// depend on the Prometheus client.
+package prometheus
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	logger "fuelers-go/packages/logger/src"
+)
+
+// Handler increments log_messages_total{level} for each record before
+// delegating to the wrapped handler.
+type Handler struct {
+	inner   slog.Handler
+	counter *prometheus.CounterVec
+}
+
+// NewCounter returns the log_messages_total counter, labelled by level.
+func NewCounter() *prometheus.CounterVec {
+	return prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "log_messages_total",
+		Help: "Number of log records emitted, by level.",
+	}, []string{"level"})
+}
+
+// NewHandler wraps h with a fresh counter. Register Collector with a
+// prometheus.Registerer to expose it:
+//
+//	h := prometheus.NewHandler(logger.Default().Handler())
+//	registry.MustRegister(h.Collector())
+//	logger.SetDefault(slog.New(h))
+func NewHandler(h slog.Handler) *Handler {
+	return NewHandlerWithCounter(h, NewCounter())
+}
+
+// NewHandlerWithCounter wraps h with an existing counter, which must have a
+// single "level" label. It lets several handlers share one metric.
+func NewHandlerWithCounter(h slog.Handler, counter *prometheus.CounterVec) *Handler {
+	return &Handler{inner: h, counter: counter}
+}
+
+// Collector returns the counter for registration.
+func (h *Handler) Collector() prometheus.Collector {
+	return h.counter
+}
+
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	h.counter.WithLabelValues(strings.ToLower(logger.LevelName(r.Level))).Inc()
+	return h.inner.Handle(ctx, r)
+}
+
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &Handler{inner: h.inner.WithAttrs(attrs), counter: h.counter}
+}
+
+func (h *Handler) WithGroup(name string) slog.Handler {
+	return &Handler{inner: h.inner.WithGroup(name), counter: h.counter}
+}
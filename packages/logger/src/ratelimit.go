@@ -0,0 +1,213 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// rateSummaryInterval is how often suppressed counts are reported.
+const rateSummaryInterval = time.Second
+
+// RateLimitSummaryMessage is the message of the record reporting how many
+// records the rate limiter dropped.
+const RateLimitSummaryMessage = "logs suppressed by rate limit"
+
+// rateLimit is one configured token bucket, applying to records at or above
+// level up to the next configured level.
+type rateLimit struct {
+	level     slog.Level
+	perSecond int
+	burst     int
+}
+
+// WithRateLimit caps records of every level at perSecond, allowing bursts of
+// up to burst. Dropped records are reported in a summary once per second.
+func WithRateLimit(perSecond, burst int) Option {
+	return WithLevelRateLimit(allLevels, perSecond, burst)
+}
+
+// WithLevelRateLimit gives records at or above level their own budget, up to
+// the next level configured with this option. Combine it with WithRateLimit
+// to throttle debug and info aggressively while leaving errors headroom:
+//
+//	logger.WithRateLimit(100, 200),
+//	logger.WithLevelRateLimit(slog.LevelError, 1000, 5000),
+func WithLevelRateLimit(level slog.Level, perSecond, burst int) Option {
+	return func(c *config) {
+		c.rateLimits = append(c.rateLimits, rateLimit{level: level, perSecond: perSecond, burst: burst})
+	}
+}
+
+// rateLimitHandler drops records once their level's bucket is empty.
+type rateLimitHandler struct {
+	inner   slog.Handler
+	limiter *rateLimiter
+}
+
+// rateLimiter holds the buckets shared by handlers derived from one logger,
+// and the handler they were derived from, which writes the summaries.
+type rateLimiter struct {
+	root slog.Handler
+	now  func() time.Time
+
+	mu      sync.Mutex
+	buckets []*tokenBucket // ascending by level
+}
+
+// tokenBucket tracks the budget and suppressed count for one level range.
+type tokenBucket struct {
+	level      slog.Level
+	rate       float64
+	burst      float64
+	tokens     float64
+	last       time.Time
+	suppressed int
+	summaryAt  time.Time // when the suppressed count is due
+}
+
+// newRateLimitHandler wraps h with the given limits. A later limit for the
+// same level replaces an earlier one.
+func newRateLimitHandler(h slog.Handler, limits []rateLimit, now func() time.Time) slog.Handler {
+	byLevel := make(map[slog.Level]rateLimit, len(limits))
+	for _, l := range limits {
+		byLevel[l.level] = l
+	}
+
+	lim := &rateLimiter{root: h, now: now}
+	for _, l := range byLevel {
+		burst := l.burst
+		if burst <= 0 {
+			burst = max(l.perSecond, 1)
+		}
+		lim.buckets = append(lim.buckets, &tokenBucket{
+			level:  l.level,
+			rate:   float64(l.perSecond),
+			burst:  float64(burst),
+			tokens: float64(burst),
+			last:   now(),
+		})
+	}
+	sort.Slice(lim.buckets, func(i, j int) bool { return lim.buckets[i].level < lim.buckets[j].level })
+
+	return &rateLimitHandler{inner: h, limiter: lim}
+}
+
+func (h *rateLimitHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+func (h *rateLimitHandler) Handle(ctx context.Context, r slog.Record) error {
+	allowed, due := h.limiter.allow(r.Level)
+	h.limiter.summarize(due)
+	if allowed {
+		return h.inner.Handle(ctx, r)
+	}
+
+	return nil
+}
+
+func (h *rateLimitHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &rateLimitHandler{inner: h.inner.WithAttrs(attrs), limiter: h.limiter}
+}
+
+func (h *rateLimitHandler) WithGroup(name string) slog.Handler {
+	return &rateLimitHandler{inner: h.inner.WithGroup(name), limiter: h.limiter}
+}
+
+// allow takes a token from the bucket for level. It also returns the
+// summaries the clock has made due, for the caller to write.
+func (l *rateLimiter) allow(level slog.Level) (bool, []rateSummary) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	due := l.due(now)
+	b := l.bucket(level)
+	if b == nil {
+		return true, due
+	}
+
+	b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, due
+	}
+
+	if b.suppressed == 0 {
+		b.summaryAt = now.Add(rateSummaryInterval)
+		l.scheduleSummary()
+	}
+	b.suppressed++
+
+	return false, due
+}
+
+// bucket returns the bucket of the highest configured level not above level.
+func (l *rateLimiter) bucket(level slog.Level) *tokenBucket {
+	var found *tokenBucket
+	for _, b := range l.buckets {
+		if b.level > level {
+			break
+		}
+		found = b
+	}
+
+	return found
+}
+
+// scheduleSummary writes the summaries due after rateSummaryInterval. With
+// WithClock the timer only checks the clock, so a summary is written on the
+// first record after the clock has moved past its interval.
+func (l *rateLimiter) scheduleSummary() {
+	time.AfterFunc(rateSummaryInterval, func() {
+		l.mu.Lock()
+		due := l.due(l.now())
+		l.mu.Unlock()
+
+		l.summarize(due)
+	})
+}
+
+// rateSummary is the suppressed count of one bucket, ready to be written.
+type rateSummary struct {
+	level      slog.Level
+	suppressed int
+}
+
+// due takes the suppressed counts of the buckets whose summary interval has
+// passed at now. It runs with the limiter lock held.
+func (l *rateLimiter) due(now time.Time) []rateSummary {
+	var due []rateSummary
+	for _, b := range l.buckets {
+		if b.suppressed > 0 && !now.Before(b.summaryAt) {
+			due = append(due, rateSummary{level: b.level, suppressed: b.suppressed})
+			b.suppressed = 0
+		}
+	}
+
+	return due
+}
+
+// summarize writes the summaries. They cover records from any request, so
+// they are written without their attributes or context, and only if the
+// logger's level lets Warn through.
+func (l *rateLimiter) summarize(due []rateSummary) {
+	ctx := context.Background()
+	if len(due) == 0 || !l.root.Enabled(ctx, slog.LevelWarn) {
+		return
+	}
+
+	for _, d := range due {
+		r := slog.NewRecord(l.now(), slog.LevelWarn, RateLimitSummaryMessage, 0)
+		r.AddAttrs(slog.Int("suppressed", d.suppressed))
+		if d.level != allLevels {
+			r.AddAttrs(slog.String("min_level", LevelName(d.level)))
+		}
+		_ = l.root.Handle(ctx, r)
+	}
+}
@@ -0,0 +1,156 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+)
+
+// syncBuffer is a bytes.Buffer for handlers that write from other goroutines.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.String()
+}
+
+// records decodes the JSON lines written to out.
+func records(t *testing.T, out string) []map[string]any {
+	t.Helper()
+
+	var recs []map[string]any
+	for _, line := range bytes.Split([]byte(out), []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		var m map[string]any
+		if err := json.Unmarshal(line, &m); err != nil {
+			t.Fatalf("decode %q: %v", line, err)
+		}
+		recs = append(recs, m)
+	}
+
+	return recs
+}
+
+func TestRateLimitSummaryHasNoRequestAttrs(t *testing.T) {
+	out := &syncBuffer{}
+	clock := newTestClock()
+	l := New(WithWriter(out), WithRateLimit(1, 1), WithClock(clock.Now))
+
+	reqA := WithFields(WithCorrelation(WithLogger(context.Background(), l), "req-A"), slog.String("user_id", "alice"))
+	reqB := WithCorrelation(WithLogger(context.Background(), l), "req-B")
+	for _, ctx := range []context.Context{reqA, reqA, reqB} {
+		Ctx(ctx).InfoContext(ctx, "work")
+	}
+	clock.Advance(rateSummaryInterval)
+	Ctx(reqA).InfoContext(reqA, "work")
+
+	var summary map[string]any
+	for _, r := range records(t, out.String()) {
+		if r["msg"] == RateLimitSummaryMessage {
+			summary = r
+		}
+	}
+
+	if summary["suppressed"] != float64(2) {
+		t.Errorf("suppressed = %v, want 2", summary["suppressed"])
+	}
+	for _, key := range []string{"trace_id", "user_id"} {
+		if v, ok := summary[key]; ok {
+			t.Errorf("summary carries %s=%v", key, v)
+		}
+	}
+}
+
+func TestRateLimitPerLevel(t *testing.T) {
+	out := &syncBuffer{}
+	clock := newTestClock()
+	limits := []rateLimit{
+		{level: allLevels, perSecond: 1, burst: 1},
+		{level: slog.LevelError, perSecond: 10, burst: 10},
+	}
+	l := slog.New(newRateLimitHandler(slog.NewJSONHandler(out, nil), limits, clock.Now))
+
+	for range 3 {
+		l.Info("info")
+		l.Error("error")
+	}
+
+	var infos, errs int
+	for _, r := range records(t, out.String()) {
+		switch r["msg"] {
+		case "info":
+			infos++
+		case "error":
+			errs++
+		}
+	}
+	if infos != 1 || errs != 3 {
+		t.Errorf("wrote %d info and %d error records, want 1 and 3", infos, errs)
+	}
+}
+
+func TestRateLimitSummaryHonorsLevel(t *testing.T) {
+	out := &syncBuffer{}
+	clock := newTestClock()
+	l := New(WithWriter(out), WithLevel(slog.LevelError), WithRateLimit(1, 1), WithClock(clock.Now))
+
+	for range 3 {
+		l.Error("boom")
+	}
+	clock.Advance(rateSummaryInterval)
+	l.Error("boom")
+
+	for _, r := range records(t, out.String()) {
+		if r["msg"] == RateLimitSummaryMessage {
+			t.Errorf("Warn summary written by an Error logger: %v", r)
+		}
+	}
+}
+
+func TestRateLimitSummaryFollowsClock(t *testing.T) {
+	out := &syncBuffer{}
+	clock := newTestClock()
+	l := New(WithWriter(out), WithRateLimit(1, 1), WithClock(clock.Now))
+
+	summaries := func() int {
+		n := 0
+		for _, r := range records(t, out.String()) {
+			if r["msg"] == RateLimitSummaryMessage {
+				n++
+			}
+		}
+		return n
+	}
+
+	l.Info("a")
+	l.Info("b")
+	// Past the wall-clock timer, with the clock still inside the interval
+	time.Sleep(rateSummaryInterval + 100*time.Millisecond)
+	if n := summaries(); n != 0 {
+		t.Fatalf("%d summaries before the clock moved", n)
+	}
+
+	clock.Advance(rateSummaryInterval)
+	l.Info("c")
+	if n := summaries(); n != 1 {
+		t.Errorf("%d summaries after the clock moved, want 1", n)
+	}
+}
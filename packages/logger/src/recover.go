@@ -0,0 +1,111 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// PanicKey is the attribute holding the value recovered from a panic.
+const PanicKey = "panic"
+
+// Recover logs a panic in the calling goroutine, with the recovered value and
+// the stack at the panic site, and lets the goroutine end normally. It must be
+// deferred directly:
+//
+//	go func() {
+//		defer logger.Recover(ctx)
+//		work(ctx)
+//	}()
+func Recover(ctx context.Context) {
+	if v := recover(); v != nil {
+		logPanic(ctx, v)
+	}
+}
+
+// RecoverRepanic is like Recover but panics again with the same value after
+// logging, for callers that still want the process to crash.
+func RecoverRepanic(ctx context.Context) {
+	if v := recover(); v != nil {
+		logPanic(ctx, v)
+		panic(v)
+	}
+}
+
+// Go runs fn on a new goroutine with ctx, so its logs keep the trace ID,
+// fields and logger of the caller, and logs a panic in fn with Recover
+// instead of crashing the process. fn is cancelled with ctx; use GoDetached
+// for work that must outlive the request.
+func Go(ctx context.Context, fn func(context.Context)) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	go func() {
+		defer Recover(ctx)
+		fn(ctx)
+	}()
+}
+
+// GoDetached is like Go but fn's context is not cancelled when ctx is, and
+// has no deadline, while keeping its values such as the trace ID.
+func GoDetached(ctx context.Context, fn func(context.Context)) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	Go(context.WithoutCancel(ctx), fn)
+}
+
+// logPanic writes the error record for a recovered value, then Syncs so the
+// record survives a subsequent crash.
+func logPanic(ctx context.Context, v any) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	// Skip [Callers, logPanic, Recover*], then the runtime's panic frames, so
+	// the trace starts where the panic happened
+	pcs := make([]uintptr, max(StackDepth, 1)+8)
+	pcs = pcs[:runtime.Callers(3, pcs)]
+	pcs = trimRuntimeFrames(pcs)
+
+	var pc uintptr
+	if len(pcs) > 0 {
+		pc = pcs[0]
+	}
+
+	r := slog.NewRecord(time.Now(), slog.LevelError, "panic recovered", pc)
+	if err, ok := v.(error); ok {
+		r.AddAttrs(slog.Any(PanicKey, err.Error()), Err(err))
+	} else {
+		r.AddAttrs(slog.Any(PanicKey, v))
+	}
+	r.AddAttrs(slog.String(StackTraceKey, formatStack(pcs)))
+
+	l := Ctx(ctx)
+	if l.Enabled(ctx, slog.LevelError) {
+		_ = l.Handler().Handle(ctx, r)
+	}
+	_ = Sync()
+}
+
+// trimRuntimeFrames drops leading runtime frames (gopanic, sigpanic, ...) and
+// caps the result at StackDepth.
+func trimRuntimeFrames(pcs []uintptr) []uintptr {
+	for len(pcs) > 0 {
+		f, _ := runtime.CallersFrames(pcs[:1]).Next()
+		if !strings.HasPrefix(f.Function, "runtime.") {
+			break
+		}
+		pcs = pcs[1:]
+	}
+
+	if len(pcs) > StackDepth {
+		pcs = pcs[:max(StackDepth, 1)]
+	}
+
+	return pcs
+}
@@ -0,0 +1,262 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+// redactedValue replaces the value of any attribute whose key is redacted.
+const redactedValue = "[REDACTED]"
+
+// WithRedactKeys masks the values of attributes with the given keys
+// (case-insensitive), at any group depth, before they reach the output.
+func WithRedactKeys(keys ...string) Option {
+	return func(c *config) {
+		c.redactKeys = append(c.redactKeys, keys...)
+	}
+}
+
+// Built-in patterns for WithRedactPatterns. A CreditCardPattern match is
+// masked only if it starts like a card of the major networks, 2 to 6, and
+// passes the Luhn check, so that IDs and Unix nanosecond timestamps of the
+// same length are left alone.
+var (
+	EmailPattern      = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	CreditCardPattern = regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`)
+)
+
+// WithRedactPatterns masks every match of the patterns inside string
+// attribute values, whatever their key. Only string values are scanned; see
+// WithRedactMessage to scan the message too.
+func WithRedactPatterns(patterns ...*regexp.Regexp) Option {
+	return func(c *config) {
+		c.redactPatterns = append(c.redactPatterns, patterns...)
+	}
+}
+
+// WithRedactMessage applies the WithRedactPatterns patterns to the message.
+func WithRedactMessage() Option {
+	return func(c *config) {
+		c.redactMessage = true
+	}
+}
+
+// redactHandler replaces the values of sensitive attributes with
+// redactedValue, and matches of patterns in strings with the same mask.
+// Records with nothing to mask pass through untouched.
+type redactHandler struct {
+	inner    slog.Handler
+	keys     []string
+	patterns []*regexp.Regexp
+	message  bool
+}
+
+// NewRedactHandler wraps h so that attributes whose key matches one of keys
+// (case-insensitive) are logged as "[REDACTED]". Groups are searched
+// recursively, both on records and on attributes added with WithAttrs.
+func NewRedactHandler(h slog.Handler, keys ...string) slog.Handler {
+	if len(keys) == 0 {
+		return h
+	}
+
+	return &redactHandler{inner: h, keys: keys}
+}
+
+// newRedactHandler is NewRedactHandler with the pattern options of New.
+func newRedactHandler(h slog.Handler, c *config) slog.Handler {
+	if len(c.redactKeys) == 0 && len(c.redactPatterns) == 0 {
+		return h
+	}
+
+	return &redactHandler{
+		inner:    h,
+		keys:     c.redactKeys,
+		patterns: c.redactPatterns,
+		message:  c.redactMessage && len(c.redactPatterns) > 0,
+	}
+}
+
+func (h *redactHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+func (h *redactHandler) Handle(ctx context.Context, r slog.Record) error {
+	msg := r.Message
+	if h.message && h.matchesPattern(msg) {
+		msg = h.mask(msg)
+	}
+	dirty := msg != r.Message
+	if !dirty {
+		r.Attrs(func(a slog.Attr) bool {
+			dirty = h.needsRedaction(a)
+			return !dirty
+		})
+	}
+	if !dirty {
+		return h.inner.Handle(ctx, r)
+	}
+
+	out := slog.NewRecord(r.Time, r.Level, msg, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		out.AddAttrs(h.redact(a))
+		return true
+	})
+
+	return h.inner.Handle(ctx, out)
+}
+
+func (h *redactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		redacted[i] = h.redact(a)
+	}
+
+	h2 := *h
+	h2.inner = h.inner.WithAttrs(redacted)
+
+	return &h2
+}
+
+func (h *redactHandler) WithGroup(name string) slog.Handler {
+	h2 := *h
+	h2.inner = h.inner.WithGroup(name)
+
+	return &h2
+}
+
+// matches reports whether key is one of the redacted keys.
+func (h *redactHandler) matches(key string) bool {
+	for _, k := range h.keys {
+		if strings.EqualFold(k, key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchesPattern reports whether s contains a match of any pattern.
+func (h *redactHandler) matchesPattern(s string) bool {
+	for _, p := range h.patterns {
+		check := patternCheck(p)
+		if check == nil {
+			if p.MatchString(s) {
+				return true
+			}
+			continue
+		}
+		for _, m := range p.FindAllString(s, -1) {
+			if check(m) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// mask replaces every pattern match in s.
+func (h *redactHandler) mask(s string) string {
+	for _, p := range h.patterns {
+		check := patternCheck(p)
+		if check == nil {
+			s = p.ReplaceAllLiteralString(s, redactedValue)
+			continue
+		}
+		s = p.ReplaceAllStringFunc(s, func(m string) string {
+			if check(m) {
+				return redactedValue
+			}
+			return m
+		})
+	}
+
+	return s
+}
+
+// patternCheck returns the test a match of p must also pass to be masked, or
+// nil when every match is.
+func patternCheck(p *regexp.Regexp) func(string) bool {
+	if p == CreditCardPattern {
+		return cardNumber
+	}
+
+	return nil
+}
+
+// cardNumber reports whether a CreditCardPattern match looks like a card.
+func cardNumber(s string) bool {
+	return s[0] >= '2' && s[0] <= '6' && luhnValid(s)
+}
+
+// luhnValid reports whether the digits in s, ignoring separators, pass the
+// Luhn checksum used by card numbers.
+func luhnValid(s string) bool {
+	sum, double := 0, false
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+
+	return sum%10 == 0
+}
+
+// needsRedaction reports whether a, or anything nested inside it, must be
+// rewritten. LogValuers are treated as dirty because their resolved value is
+// unknown until they are evaluated.
+func (h *redactHandler) needsRedaction(a slog.Attr) bool {
+	if h.matches(a.Key) {
+		return true
+	}
+
+	switch a.Value.Kind() {
+	case slog.KindString:
+		return len(h.patterns) > 0 && h.matchesPattern(a.Value.String())
+	case slog.KindLogValuer:
+		return true
+	case slog.KindGroup:
+		for _, ga := range a.Value.Group() {
+			if h.needsRedaction(ga) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// redact returns a with sensitive values masked, descending into groups.
+func (h *redactHandler) redact(a slog.Attr) slog.Attr {
+	if h.matches(a.Key) {
+		return slog.String(a.Key, redactedValue)
+	}
+
+	a.Value = a.Value.Resolve()
+	if a.Value.Kind() == slog.KindString && len(h.patterns) > 0 {
+		return slog.String(a.Key, h.mask(a.Value.String()))
+	}
+	if a.Value.Kind() != slog.KindGroup {
+		return a
+	}
+
+	group := a.Value.Group()
+	attrs := make([]slog.Attr, len(group))
+	for i, ga := range group {
+		attrs[i] = h.redact(ga)
+	}
+
+	return slog.Attr{Key: a.Key, Value: slog.GroupValue(attrs...)}
+}
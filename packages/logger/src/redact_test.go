@@ -0,0 +1,43 @@
+package logger
+
+import "testing"
+
+func TestCreditCardPatternLuhn(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"card 4111 1111 1111 1111 ok", "card " + redactedValue + " ok"},
+		{"5555-5555-5555-4444", redactedValue},
+		{"4111111111111112", "4111111111111112"},
+		{"1789012345678901234", "1789012345678901234"},
+		{"1700000000000000000", "1700000000000000000"},
+	}
+	for _, tt := range tests {
+		out := &syncBuffer{}
+		New(WithWriter(out), WithRedactPatterns(CreditCardPattern)).Info("m", "v", tt.in)
+
+		if got := records(t, out.String())[0]["v"]; got != tt.want {
+			t.Errorf("%q logged as %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRedactIDs(t *testing.T) {
+	out := &syncBuffer{}
+	New(WithWriter(out), WithRedactPatterns(CreditCardPattern)).Info("m", ID("order_id", 1789012345678901234))
+
+	if got := records(t, out.String())[0]["order_id"]; got != "1789012345678901234" {
+		t.Errorf("order_id = %v", got)
+	}
+}
+
+func TestRedactKeysAndMessage(t *testing.T) {
+	out := &syncBuffer{}
+	New(WithWriter(out), WithRedactKeys("password"), WithRedactPatterns(EmailPattern), WithRedactMessage()).
+		With("Password", "hunter2").Info("mail a@b.io")
+
+	r := records(t, out.String())[0]
+	if r["Password"] != redactedValue || r["msg"] != "mail "+redactedValue {
+		t.Errorf("got %v", r)
+	}
+}
@@ -0,0 +1,58 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+// maxEarlyErrors bounds the errors kept for replay to the first reporter.
+const maxEarlyErrors = 16
+
+// reporter receives the package's own configuration and runtime errors,
+// such as an invalid LOG_LEVEL or a log file that cannot be opened.
+var (
+	reporterMu  sync.Mutex
+	reporter    func(error)
+	earlyErrors []error
+)
+
+// SetErrorReporter decides how the package surfaces its own errors, such as
+// invalid LOG_* values, unwritable log files or panicking hooks. By default
+// they are written to os.Stderr. Errors reported before the first call,
+// including those found during init, are replayed to fn. Passing nil
+// restores the default.
+func SetErrorReporter(fn func(error)) {
+	reporterMu.Lock()
+	replay := earlyErrors
+	if reporter == nil {
+		earlyErrors = nil
+	} else {
+		replay = nil
+	}
+	reporter = fn
+	reporterMu.Unlock()
+
+	if fn != nil {
+		for _, err := range replay {
+			fn(err)
+		}
+	}
+}
+
+// reportError hands err to the configured reporter.
+func reportError(err error) {
+	reporterMu.Lock()
+	fn := reporter
+	if fn == nil && len(earlyErrors) < maxEarlyErrors {
+		earlyErrors = append(earlyErrors, err)
+	}
+	reporterMu.Unlock()
+
+	if fn == nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+
+	fn(err)
+}
@@ -0,0 +1,137 @@
+package logger
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// resetReporter clears the reporter and early errors for the rest of the
+// test, as if no error had been reported yet.
+func resetReporter(t *testing.T) {
+	t.Helper()
+
+	reporterMu.Lock()
+	prev, prevEarly := reporter, earlyErrors
+	reporter, earlyErrors = nil, nil
+	reporterMu.Unlock()
+
+	t.Cleanup(func() {
+		reporterMu.Lock()
+		reporter, earlyErrors = prev, prevEarly
+		reporterMu.Unlock()
+	})
+}
+
+// silenceStderr discards the default reporter's output for the test.
+func silenceStderr(t *testing.T) {
+	t.Helper()
+
+	null, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stderr := os.Stderr
+	os.Stderr = null
+	t.Cleanup(func() {
+		os.Stderr = stderr
+		null.Close()
+	})
+}
+
+// collect returns a reporter appending to the returned slice.
+func collect() (func(error), func() []error) {
+	var (
+		mu   sync.Mutex
+		errs []error
+	)
+	report := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		errs = append(errs, err)
+	}
+	got := func() []error {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]error(nil), errs...)
+	}
+
+	return report, got
+}
+
+func TestInvalidLevelEnvReplayed(t *testing.T) {
+	resetReporter(t)
+	prevOpts, prevLevel := envOpts, level.Level()
+	t.Cleanup(func() {
+		envOpts = prevOpts
+		level.Set(prevLevel)
+	})
+	t.Setenv(LevelEnv, "loud")
+	t.Setenv(FormatEnv, "")
+	t.Setenv(SourceEnv, "")
+
+	// Reported before any reporter is set, as during init
+	silenceStderr(t)
+	loadEnv()
+
+	report, got := collect()
+	SetErrorReporter(report)
+	t.Cleanup(func() { SetErrorReporter(nil) })
+
+	errs := got()
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), LevelEnv) {
+		t.Fatalf("replayed %v, want the invalid %s", errs, LevelEnv)
+	}
+
+	// Replayed to the first reporter only
+	again, gotAgain := collect()
+	SetErrorReporter(again)
+	if errs := gotAgain(); len(errs) != 0 {
+		t.Errorf("replayed %v to a second reporter", errs)
+	}
+}
+
+func TestWithFileFailureReported(t *testing.T) {
+	resetReporter(t)
+	report, got := collect()
+	SetErrorReporter(report)
+	t.Cleanup(func() { SetErrorReporter(nil) })
+
+	// A regular file where the log directory should be
+	dir := t.TempDir()
+	blocker := filepath.Join(dir, "blocker")
+	if err := os.WriteFile(blocker, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	out := &syncBuffer{}
+	New(WithWriter(out), WithFile(filepath.Join(blocker, "app.log"), RotateOptions{})).Info("still here")
+
+	if errs := got(); len(errs) != 1 || !strings.Contains(errs[0].Error(), "log directory") {
+		t.Errorf("reported %v, want the directory error", errs)
+	}
+	if recs := records(t, out.String()); len(recs) != 1 || recs[0][slog.MessageKey] != "still here" {
+		t.Errorf("previous writer got %v", recs)
+	}
+}
+
+func TestEarlyErrorsBounded(t *testing.T) {
+	resetReporter(t)
+
+	silenceStderr(t)
+	for i := range maxEarlyErrors + 4 {
+		reportError(fmt.Errorf("error %d", i))
+	}
+
+	report, got := collect()
+	SetErrorReporter(report)
+	t.Cleanup(func() { SetErrorReporter(nil) })
+
+	if n := len(got()); n != maxEarlyErrors {
+		t.Errorf("replayed %d errors, want %d", n, maxEarlyErrors)
+	}
+}
@@ -0,0 +1,121 @@
+package logger
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+)
+
+// Resources are the files and background writers opened by options such as
+// WithFile, WithAsync and WithDedup, and the handlers given to WithHandlers.
+// Those of loggers built with New belong to the package and are released by
+// Close; Open gives a logger its own.
+type Resources struct {
+	mu      sync.Mutex
+	closers []io.Closer
+}
+
+// resources holds what loggers built with New open.
+var resources Resources
+
+// add tracks c so that Close releases it.
+func (r *Resources) add(c io.Closer) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.closers = append(r.closers, c)
+}
+
+// addHandler tracks h if it buffers or holds resources, as BatchHandler does.
+func (r *Resources) addHandler(h slog.Handler) {
+	switch h := h.(type) {
+	case io.Closer:
+		r.add(h)
+	case Flusher:
+		r.add(flushOnly{h})
+	}
+}
+
+// flushOnly tracks a Flusher with nothing to close.
+type flushOnly struct {
+	Flusher
+}
+
+func (flushOnly) Close() error {
+	return nil
+}
+
+// Sync flushes the background writers and files so that records logged
+// before the call are written and committed to storage. Asynchronous writers
+// are drained before the files they write to are synced.
+func (r *Resources) Sync() error {
+	r.mu.Lock()
+	closers := append([]io.Closer(nil), r.closers...)
+	r.mu.Unlock()
+
+	var errs []error
+	for i := len(closers) - 1; i >= 0; i-- {
+		if f, ok := closers[i].(Flusher); ok {
+			errs = append(errs, f.Flush())
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Close releases everything, most recent first, so background writers drain
+// into their files before those are closed. Call it after the last log call;
+// later calls do nothing.
+func (r *Resources) Close() error {
+	r.mu.Lock()
+	closers := r.closers
+	r.closers = nil
+	r.mu.Unlock()
+
+	var errs []error
+	for i := len(closers) - 1; i >= 0; i-- {
+		if err := closers[i].Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Open is New for loggers that do not live as long as the process, such as
+// per-component ones. What its options open is not tracked by the package
+// Sync and Close but by the returned Resources, which must be closed once
+// the logger is no longer used:
+//
+//	l, res := logger.Open(logger.WithAsync(logger.AsyncOptions{}))
+//	defer res.Close()
+func Open(opts ...Option) (*slog.Logger, *Resources) {
+	res := &Resources{}
+	return build(res, opts), res
+}
+
+// Sync flushes the default handler, if it buffers, and every handler and file
+// opened by logger options of loggers built with New, so that records logged
+// before the call are written and committed to storage. Asynchronous writers
+// are drained before the files they write to are synced. Records logged
+// concurrently with Sync may or may not be included. With the plain stdout
+// handler it does nothing, so callers can always defer it:
+//
+//	defer logger.Sync()
+func Sync() error {
+	var errs []error
+	if f, ok := Default().Handler().(Flusher); ok {
+		errs = append(errs, f.Flush())
+	}
+	errs = append(errs, resources.Sync())
+
+	return errors.Join(errs...)
+}
+
+// Close releases everything opened by logger options of loggers built with
+// New, most recent first, so background writers drain into their files
+// before those are closed. Call it once on shutdown, after the last log call.
+func Close() error {
+	return resources.Close()
+}
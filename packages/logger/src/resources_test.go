@@ -0,0 +1,71 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// globalResources returns how many closers New has registered.
+func globalResources() int {
+	resources.mu.Lock()
+	defer resources.mu.Unlock()
+
+	return len(resources.closers)
+}
+
+func TestOpenKeepsResourcesLocal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "component.log")
+	before := globalResources()
+
+	l, res := Open(WithFile(path, RotateOptions{}), WithAsync(AsyncOptions{}), WithDedup(time.Minute))
+	l.Info("queued")
+
+	if n := globalResources(); n != before {
+		t.Errorf("Open registered %d package resources", n-before)
+	}
+	if err := res.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := os.ReadFile(path); !strings.Contains(string(got), `"msg":"queued"`) {
+		t.Errorf("Sync did not write the record: %q", got)
+	}
+	if err := res.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := res.Close(); err != nil {
+		t.Errorf("second Close: %v", err)
+	}
+}
+
+func TestOpenCloseStopsGoroutines(t *testing.T) {
+	before := runtime.NumGoroutine()
+	for range 50 {
+		_, res := Open(WithWriter(&syncBuffer{}), WithAsync(AsyncOptions{}), WithDedup(time.Minute))
+		res.Close()
+	}
+
+	// Background goroutines exit shortly after Close returns
+	waitFor(t, func() bool { return runtime.NumGoroutine() <= before+2 })
+}
+
+func TestNewRegistersResources(t *testing.T) {
+	before := globalResources()
+	l := New(WithWriter(&syncBuffer{}), WithAsync(AsyncOptions{}))
+	t.Cleanup(func() {
+		// Release just this logger's writer, leaving the rest registered
+		resources.mu.Lock()
+		c := resources.closers[len(resources.closers)-1]
+		resources.closers = resources.closers[:len(resources.closers)-1]
+		resources.mu.Unlock()
+		c.Close()
+	})
+	l.Info("x")
+
+	if n := globalResources(); n != before+1 {
+		t.Errorf("New registered %d resources, want 1", n-before)
+	}
+}
@@ -0,0 +1,311 @@
+package logger
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupTimeFormat names rotated files so they sort chronologically.
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// RotateOptions controls when a RotatingFile rolls over and how many old
+// files are kept. Zero values disable the corresponding limit.
+type RotateOptions struct {
+	// MaxSizeMB rotates the file before a write would push it past this size.
+	MaxSizeMB int
+	// Interval rotates the file once it has been open this long.
+	Interval time.Duration
+	// MaxAge deletes rotated files older than this.
+	MaxAge time.Duration
+	// MaxBackups keeps at most this many rotated files.
+	MaxBackups int
+	// Compress gzips rotated files.
+	Compress bool
+}
+
+// RotatingFile is an io.Writer that appends to a file and rotates it by size
+// or age. Rotated files are renamed to <name>-<timestamp><ext> next to the
+// original. It is safe for concurrent use.
+type RotatingFile struct {
+	path string
+	opts RotateOptions
+
+	mu       sync.Mutex
+	file     *os.File
+	closed   bool
+	size     int64
+	openedAt time.Time
+
+	millMu sync.Mutex // serializes compression and cleanup of backups
+}
+
+// OpenRotatingFile opens path for appending, creating it and its directory
+// if needed.
+func OpenRotatingFile(path string, opts RotateOptions) (*RotatingFile, error) {
+	f := &RotatingFile{path: path, opts: opts}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// WithFile writes output to a RotatingFile at path instead of the configured
+// writer. The file is released by Close, or by the Resources of Open. If it
+// cannot be opened, the logger keeps its previous writer and the error goes
+// to the error reporter.
+func WithFile(path string, opts RotateOptions) Option {
+	return func(c *config) {
+		f, err := OpenRotatingFile(path, opts)
+		if err != nil {
+			reportError(err)
+			return
+		}
+
+		c.resources.add(f)
+		c.writer = f
+	}
+}
+
+// Write appends p, rotating first if the size or interval limit is reached.
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.ensureOpen(); err != nil {
+		return 0, err
+	}
+
+	if f.due(len(p)) {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+
+	return n, err
+}
+
+// Rotate rolls the file over immediately.
+func (f *RotatingFile) Rotate() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.ensureOpen(); err != nil {
+		return err
+	}
+
+	return f.rotate()
+}
+
+// Flush commits written data to stable storage.
+func (f *RotatingFile) Flush() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return nil
+	}
+
+	return f.file.Sync()
+}
+
+// Close flushes and closes the file. Further writes fail with os.ErrClosed.
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.closed = true
+	if f.file == nil {
+		return nil
+	}
+
+	err := errors.Join(f.file.Sync(), f.file.Close())
+	f.file = nil
+
+	return err
+}
+
+// ensureOpen reopens the active file after a failed rotation left it closed.
+func (f *RotatingFile) ensureOpen() error {
+	if f.closed {
+		return os.ErrClosed
+	}
+	if f.file != nil {
+		return nil
+	}
+
+	return f.open()
+}
+
+// due reports whether writing n more bytes requires a rotation first.
+func (f *RotatingFile) due(n int) bool {
+	if max := int64(f.opts.MaxSizeMB) * 1024 * 1024; max > 0 && f.size > 0 && f.size+int64(n) > max {
+		return true
+	}
+
+	return f.opts.Interval > 0 && time.Since(f.openedAt) >= f.opts.Interval
+}
+
+// open opens or creates the active file.
+func (f *RotatingFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o755); err != nil {
+		return fmt.Errorf("logger: create log directory: %w", err)
+	}
+
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("logger: open log file: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("logger: stat log file: %w", err)
+	}
+
+	f.file = file
+	f.size = info.Size()
+	f.openedAt = time.Now()
+
+	return nil
+}
+
+// rotate renames the active file to a timestamped backup and opens a fresh
+// one. Compression and cleanup run in the background. If the rename fails,
+// writing continues into the original file; if opening the fresh one fails,
+// the next write retries.
+func (f *RotatingFile) rotate() error {
+	err := f.file.Close()
+	f.file = nil
+	if err != nil {
+		return fmt.Errorf("logger: close log file: %w", err)
+	}
+
+	ext := filepath.Ext(f.path)
+	backup := fmt.Sprintf("%s-%s%s", strings.TrimSuffix(f.path, ext), time.Now().UTC().Format(backupTimeFormat), ext)
+	if err := os.Rename(f.path, backup); err != nil && !os.IsNotExist(err) {
+		return errors.Join(fmt.Errorf("logger: rotate log file: %w", err), f.open())
+	}
+
+	if err := f.open(); err != nil {
+		return err
+	}
+
+	go f.mill(backup)
+
+	return nil
+}
+
+// mill compresses the new backup and prunes old ones. Errors are reported but
+// never stop logging.
+func (f *RotatingFile) mill(backup string) {
+	f.millMu.Lock()
+	defer f.millMu.Unlock()
+
+	if f.opts.Compress {
+		if err := compressFile(backup); err != nil {
+			reportError(fmt.Errorf("logger: compress %s: %w", backup, err))
+		} else {
+			_ = os.Remove(backup)
+		}
+	}
+
+	if f.opts.MaxBackups <= 0 && f.opts.MaxAge <= 0 {
+		return
+	}
+
+	backups := f.backups()
+	for i, b := range backups {
+		expired := f.opts.MaxAge > 0 && time.Since(b.modTime) > f.opts.MaxAge
+		surplus := f.opts.MaxBackups > 0 && i >= f.opts.MaxBackups
+		if expired || surplus {
+			_ = os.Remove(b.path)
+		}
+	}
+}
+
+// backupFile is a rotated file found on disk.
+type backupFile struct {
+	path    string
+	modTime time.Time
+}
+
+// backups lists rotated files for this path, newest first.
+func (f *RotatingFile) backups() []backupFile {
+	ext := filepath.Ext(f.path)
+	prefix := filepath.Base(strings.TrimSuffix(f.path, ext)) + "-"
+	dir := filepath.Dir(f.path)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	var out []backupFile
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !isBackup(name, prefix, ext) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		out = append(out, backupFile{path: filepath.Join(dir, name), modTime: info.ModTime()})
+	}
+
+	// Timestamped names sort chronologically
+	sort.Slice(out, func(i, j int) bool { return out[i].path > out[j].path })
+
+	return out
+}
+
+// isBackup reports whether name is prefix, a backup timestamp and ext, with
+// an optional .gz, so that neighbours such as app-audit.log are left alone.
+func isBackup(name, prefix, ext string) bool {
+	stamp, ok := strings.CutPrefix(name, prefix)
+	if !ok {
+		return false
+	}
+	stamp = strings.TrimSuffix(stamp, ".gz")
+	if stamp, ok = strings.CutSuffix(stamp, ext); !ok {
+		return false
+	}
+
+	_, err := time.Parse(backupTimeFormat, stamp)
+	return err == nil
+}
+
+// compressFile writes a gzip copy of path to path+".gz".
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+
+	zw := gzip.NewWriter(dst)
+	_, err = io.Copy(zw, src)
+	err = errors.Join(err, zw.Close(), dst.Close())
+	if err != nil {
+		_ = os.Remove(path + ".gz")
+	}
+
+	return err
+}
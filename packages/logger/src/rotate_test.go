@@ -0,0 +1,164 @@
+package logger
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// waitFor polls cond, for checks on the background compression and cleanup.
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func openTestFile(t *testing.T, path string, opts RotateOptions) *RotatingFile {
+	t.Helper()
+
+	f, err := OpenRotatingFile(path, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { f.Close() })
+
+	return f
+}
+
+// backupNames lists the rotated files next to path.
+func backupNames(t *testing.T, path string) []string {
+	t.Helper()
+
+	f := &RotatingFile{path: path}
+	var names []string
+	for _, b := range f.backups() {
+		names = append(names, filepath.Base(b.path))
+	}
+
+	return names
+}
+
+func TestRotatingFileRotatesBySize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	f := openTestFile(t, path, RotateOptions{MaxSizeMB: 1})
+
+	first := bytes.Repeat([]byte("a"), 600*1024)
+	second := bytes.Repeat([]byte("b"), 600*1024)
+	for _, p := range [][]byte{first, second} {
+		if _, err := f.Write(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	backups := backupNames(t, path)
+	if len(backups) != 1 {
+		t.Fatalf("backups = %v, want one", backups)
+	}
+	if got, _ := os.ReadFile(filepath.Join(filepath.Dir(path), backups[0])); !bytes.Equal(got, first) {
+		t.Errorf("backup holds %d bytes, want the first write", len(got))
+	}
+	if got, _ := os.ReadFile(path); !bytes.Equal(got, second) {
+		t.Errorf("active file holds %d bytes, want the second write", len(got))
+	}
+}
+
+func TestRotatingFilePrunesOnlyBackups(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	for _, name := range []string{"app-audit.log", "app-errors.log", "app-2020-01-01T00-00-00.000.log", "app-2020-01-02T00-00-00.000.log.gz"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	f := openTestFile(t, path, RotateOptions{MaxBackups: 1})
+	if err := f.Rotate(); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, func() bool { return len(backupNames(t, path)) == 1 })
+
+	if b := backupNames(t, path)[0]; strings.HasPrefix(b, "app-2020") {
+		t.Errorf("kept %s, want the newest backup", b)
+	}
+	for _, name := range []string{"app-audit.log", "app-errors.log"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("unrelated %s: %v", name, err)
+		}
+	}
+}
+
+func TestRotatingFileCompresses(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	f := openTestFile(t, path, RotateOptions{Compress: true})
+
+	if _, err := f.Write([]byte("hello\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Rotate(); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, func() bool {
+		b := backupNames(t, path)
+		return len(b) == 1 && strings.HasSuffix(b[0], ".gz")
+	})
+
+	gz, err := os.Open(filepath.Join(filepath.Dir(path), backupNames(t, path)[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer gz.Close()
+	zr, err := gzip.NewReader(gz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := io.ReadAll(zr); string(got) != "hello\n" {
+		t.Errorf("compressed backup = %q", got)
+	}
+}
+
+func TestRotatingFileRecoversFromFailedRotate(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "logs")
+	path := filepath.Join(dir, "app.log")
+	f := openTestFile(t, path, RotateOptions{})
+
+	// A file where the directory was fails both the rename and the reopen
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(dir, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Rotate(); err == nil {
+		t.Fatal("Rotate succeeded, want an error")
+	}
+
+	if err := os.Remove(dir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write([]byte("after\n")); err != nil {
+		t.Fatalf("Write after failed rotation: %v", err)
+	}
+	if got, _ := os.ReadFile(path); string(got) != "after\n" {
+		t.Errorf("active file = %q", got)
+	}
+}
+
+func TestRotatingFileClosed(t *testing.T) {
+	f := openTestFile(t, filepath.Join(t.TempDir(), "app.log"), RotateOptions{})
+	f.Close()
+
+	if _, err := f.Write([]byte("x")); err != os.ErrClosed {
+		t.Errorf("Write after Close = %v, want os.ErrClosed", err)
+	}
+}
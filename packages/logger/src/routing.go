@@ -0,0 +1,128 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+)
+
+// LevelRoute sends the records in a level range to their own output.
+type LevelRoute struct {
+	// Min and Max bound the range, inclusive. A nil bound is open.
+	Min, Max slog.Leveler
+	// Handler receives the matching records. When nil, the logger's output
+	// is rebuilt with Options applied on top, e.g. WithPrettyJSON or
+	// WithWriter(os.Stderr); only output options such as the writer, format
+	// and time or source settings take effect there.
+	Handler slog.Handler
+	Options []Option
+	// Continue lets a matching record go on to later routes and to the
+	// logger's own output, instead of stopping at this route.
+	Continue bool
+}
+
+// WithLevelRouting routes records by level, generalizing
+// WithStderrRouting. Routes are tried in order and a record goes to the
+// first one matching its level; records matching none go to the logger's
+// own output. Routes with Continue let a record reach several outputs.
+func WithLevelRouting(routes []LevelRoute) Option {
+	return func(c *config) {
+		c.routes = append(c.routes, routes...)
+	}
+}
+
+// route is a LevelRoute with its handler built.
+type route struct {
+	min, max slog.Leveler
+	handler  slog.Handler
+	cont     bool
+}
+
+func (r route) matches(level slog.Level) bool {
+	return (r.min == nil || level >= r.min.Level()) && (r.max == nil || level <= r.max.Level())
+}
+
+// output builds the config's output handler, split by the routes of
+// WithLevelRouting and WithStderrRouting.
+func (c *config) output() slog.Handler {
+	routes := c.routes
+	if c.stderr != nil {
+		routes = append(routes[:len(routes):len(routes)], c.stderrRoute())
+	}
+	if len(routes) == 0 {
+		return c.handler()
+	}
+
+	rh := &routeHandler{fallback: c.handler()}
+	for _, lr := range routes {
+		h := lr.Handler
+		if h == nil {
+			rc := *c
+			rc.routes, rc.stderr = nil, nil
+			for _, opt := range lr.Options {
+				opt(&rc)
+			}
+			h = rc.handler()
+		}
+		rh.routes = append(rh.routes, route{min: lr.Min, max: lr.Max, handler: h, cont: lr.Continue})
+	}
+
+	return rh
+}
+
+// routeHandler dispatches each record to its matching routes.
+type routeHandler struct {
+	routes   []route
+	fallback slog.Handler
+}
+
+func (h *routeHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, r := range h.routes {
+		if r.matches(level) {
+			if r.handler.Enabled(ctx, level) {
+				return true
+			}
+			if !r.cont {
+				return false
+			}
+		}
+	}
+
+	return h.fallback.Enabled(ctx, level)
+}
+
+func (h *routeHandler) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+	for _, rt := range h.routes {
+		if !rt.matches(r.Level) {
+			continue
+		}
+		if !rt.cont {
+			return errors.Join(append(errs, rt.handler.Handle(ctx, r))...)
+		}
+		if rt.handler.Enabled(ctx, r.Level) {
+			errs = append(errs, rt.handler.Handle(ctx, r.Clone()))
+		}
+	}
+
+	return errors.Join(append(errs, h.fallback.Handle(ctx, r))...)
+}
+
+func (h *routeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return h.derive(func(inner slog.Handler) slog.Handler { return inner.WithAttrs(attrs) })
+}
+
+func (h *routeHandler) WithGroup(name string) slog.Handler {
+	return h.derive(func(inner slog.Handler) slog.Handler { return inner.WithGroup(name) })
+}
+
+// derive applies fn to every output.
+func (h *routeHandler) derive(fn func(slog.Handler) slog.Handler) slog.Handler {
+	h2 := &routeHandler{routes: make([]route, len(h.routes)), fallback: fn(h.fallback)}
+	for i, r := range h.routes {
+		r.handler = fn(r.handler)
+		h2.routes[i] = r
+	}
+
+	return h2
+}
@@ -0,0 +1,141 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// messages returns the messages of the JSON records written to out.
+func messages(t *testing.T, out *syncBuffer) string {
+	t.Helper()
+
+	var msgs []string
+	for _, r := range records(t, out.String()) {
+		msgs = append(msgs, r[slog.MessageKey].(string))
+	}
+
+	return strings.Join(msgs, ",")
+}
+
+func TestLevelRoutingFirstMatch(t *testing.T) {
+	out, errs, warns := &syncBuffer{}, &syncBuffer{}, &syncBuffer{}
+	l := New(WithWriter(out), WithLevelRouting([]LevelRoute{
+		{Min: slog.LevelError, Handler: slog.NewJSONHandler(errs, nil)},
+		{Min: slog.LevelWarn, Handler: slog.NewJSONHandler(warns, nil)},
+	}))
+
+	l.Info("info")
+	l.Warn("warn")
+	l.Error("error")
+
+	tests := []struct {
+		name string
+		out  *syncBuffer
+		want string
+	}{
+		{"output", out, "info"},
+		{"error route", errs, "error"},
+		{"warn route", warns, "warn"},
+	}
+	for _, tt := range tests {
+		if got := messages(t, tt.out); got != tt.want {
+			t.Errorf("%s got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLevelRoutingOptions(t *testing.T) {
+	out, debug := &syncBuffer{}, &syncBuffer{}
+	l := New(WithWriter(out), WithFormat(FormatLogfmt), WithLevel(LevelTrace), WithLevelRouting([]LevelRoute{
+		{Max: slog.LevelDebug, Options: []Option{WithWriter(debug)}},
+	})).With("svc", "api")
+
+	l.Debug("low")
+	l.Info("high")
+
+	if got := debug.String(); !strings.Contains(got, "msg=low") || !strings.Contains(got, "svc=api") || strings.Contains(got, "high") {
+		t.Errorf("route got %q, want the debug record in the logger's logfmt format", got)
+	}
+	if got := out.String(); !strings.Contains(got, "msg=high") || strings.Contains(got, "low") {
+		t.Errorf("output got %q", got)
+	}
+}
+
+func TestLevelRoutingContinue(t *testing.T) {
+	out, alerts, errs := &syncBuffer{}, &syncBuffer{}, &syncBuffer{}
+	l := New(WithWriter(out), WithLevelRouting([]LevelRoute{
+		{Min: slog.LevelWarn, Handler: slog.NewJSONHandler(alerts, nil), Continue: true},
+		{Min: slog.LevelError, Handler: slog.NewJSONHandler(errs, nil)},
+	}))
+
+	l.Info("info")
+	l.Warn("warn")
+	l.Error("error")
+
+	if got := messages(t, alerts); got != "warn,error" {
+		t.Errorf("continue route got %q", got)
+	}
+	if got := messages(t, errs); got != "error" {
+		t.Errorf("error route got %q", got)
+	}
+	if got := messages(t, out); got != "info,warn" {
+		t.Errorf("output got %q, want records not stopped by a route", got)
+	}
+}
+
+func TestLevelRoutingEnabled(t *testing.T) {
+	strict := slog.NewJSONHandler(&syncBuffer{}, &slog.HandlerOptions{Level: slog.LevelError})
+	h := New(WithWriter(&syncBuffer{}), WithLevelRouting([]LevelRoute{
+		{Min: slog.LevelWarn, Max: slog.LevelWarn, Handler: strict},
+		{Min: slog.LevelError, Handler: strict, Continue: true},
+	})).Handler()
+
+	ctx := context.Background()
+	tests := []struct {
+		level slog.Level
+		want  bool
+	}{
+		{slog.LevelDebug, false}, // below the logger's level
+		{slog.LevelInfo, true},   // no route, the output takes it
+		{slog.LevelWarn, false},  // stops at a route that rejects it
+		{slog.LevelError, true},
+	}
+	for _, tt := range tests {
+		if got := h.Enabled(ctx, tt.level); got != tt.want {
+			t.Errorf("Enabled(%v) = %v, want %v", tt.level, got, tt.want)
+		}
+	}
+}
+
+func TestStderrRouting(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stderr")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stderr := os.Stderr
+	os.Stderr = f
+	t.Cleanup(func() {
+		os.Stderr = stderr
+		f.Close()
+	})
+
+	out, mirrored := &syncBuffer{}, &syncBuffer{}
+	New(WithWriter(out), WithErrorToStderr()).Error("split")
+	New(WithWriter(mirrored), WithStderrRouting(slog.LevelWarn, true)).Warn("mirrored")
+
+	got, _ := os.ReadFile(path)
+	if !strings.Contains(string(got), `"msg":"split"`) || !strings.Contains(string(got), `"msg":"mirrored"`) {
+		t.Errorf("stderr got %q", got)
+	}
+	if out.String() != "" {
+		t.Errorf("routed error also written to the output: %q", out)
+	}
+	if messages(t, mirrored) != "mirrored" {
+		t.Errorf("mirrored record missing from the output: %q", mirrored)
+	}
+}
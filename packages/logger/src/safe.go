@@ -0,0 +1,242 @@
+package logger
+
+import (
+	"encoding"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Bounds applied by Safe. Set them once at startup.
+var (
+	// SafeMaxDepth is how deeply Safe descends into nested values.
+	SafeMaxDepth = 5
+	// SafeMaxSize approximates the largest rendering, in bytes, Safe emits
+	// before replacing the whole value with its type name.
+	SafeMaxSize = 4096
+)
+
+// Safe returns a bounded rendering of v, for logging config structs, maps
+// and other rich values without the risks of slog.Any. Structs and maps
+// become groups; struct fields are named after their json tag, if any, and
+// unexported fields are skipped. A field tagged `log:"-"` is omitted and one
+// tagged `log:"redact"` is logged as "[REDACTED]". Values nested deeper than
+// SafeMaxDepth are replaced by their type name with TruncatedSuffix, and so
+// is the whole value when it renders larger than SafeMaxSize.
+func Safe(v any) slog.Value {
+	s := &safeWalker{budget: SafeMaxSize}
+	out := s.value(reflect.ValueOf(v), 0)
+	if s.budget < 0 {
+		return slog.StringValue(safeTypeName(reflect.ValueOf(v)) + TruncatedSuffix)
+	}
+
+	return out
+}
+
+// safeWalker converts a value while tracking the remaining size budget.
+type safeWalker struct {
+	budget int
+}
+
+var (
+	logValuerType = reflect.TypeFor[slog.LogValuer]()
+	errorType     = reflect.TypeFor[error]()
+)
+
+// value converts rv to a slog.Value, nesting maps and structs as groups.
+func (s *safeWalker) value(rv reflect.Value, depth int) slog.Value {
+	if s.budget < 0 {
+		return slog.Value{}
+	}
+	for rv.Kind() == reflect.Interface || rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			s.budget -= 4
+			return slog.AnyValue(nil)
+		}
+		if rv.Type().Implements(logValuerType) || rv.Type().Implements(errorType) {
+			break
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		s.budget -= 4
+		return slog.AnyValue(nil)
+	}
+
+	if v, ok := s.special(rv, depth); ok {
+		return v
+	}
+
+	switch rv.Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+		if depth >= SafeMaxDepth {
+			return s.str(safeTypeName(rv) + TruncatedSuffix)
+		}
+	}
+
+	switch rv.Kind() {
+	case reflect.Struct:
+		return slog.GroupValue(s.structAttrs(rv, depth)...)
+	case reflect.Map:
+		return slog.GroupValue(s.mapAttrs(rv, depth)...)
+	case reflect.Slice, reflect.Array:
+		// Give up on what cannot fit before walking or copying it
+		if rv.Len() > s.budget {
+			s.budget = -1
+			return slog.Value{}
+		}
+		if rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8 {
+			return s.str(string(rv.Bytes()))
+		}
+		items := make([]any, 0, rv.Len())
+		for i := 0; i < rv.Len() && s.budget >= 0; i++ {
+			s.budget--
+			items = append(items, plain(s.value(rv.Index(i), depth+1)))
+		}
+		s.budget -= 2
+		return slog.AnyValue(items)
+	case reflect.String:
+		return s.str(rv.String())
+	case reflect.Bool:
+		s.budget -= 5
+		return slog.BoolValue(rv.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		s.budget -= 8
+		return slog.Int64Value(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		s.budget -= 8
+		return slog.Uint64Value(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		s.budget -= 8
+		return slog.Float64Value(rv.Float())
+	}
+
+	// Channels, funcs and the like have no useful rendering
+	return s.str(safeTypeName(rv))
+}
+
+// special handles the types with their own textual form.
+func (s *safeWalker) special(rv reflect.Value, depth int) (slog.Value, bool) {
+	if !rv.CanInterface() {
+		return slog.Value{}, false
+	}
+
+	switch x := rv.Interface().(type) {
+	case time.Time:
+		s.budget -= 30
+		return slog.TimeValue(x), true
+	case time.Duration:
+		s.budget -= 8
+		return slog.DurationValue(x), true
+	case slog.LogValuer:
+		// Walk the resolved value, which may itself be rich
+		v := x.LogValue().Resolve()
+		if v.Kind() == slog.KindGroup {
+			s.budget -= len(v.String())
+			return v, true
+		}
+		return s.value(reflect.ValueOf(v.Any()), depth+1), true
+	case error:
+		return s.str(x.Error()), true
+	case encoding.TextMarshaler:
+		if b, err := x.MarshalText(); err == nil {
+			return s.str(string(b)), true
+		}
+	}
+
+	return slog.Value{}, false
+}
+
+// structAttrs converts the exported fields of rv, honoring log tags.
+func (s *safeWalker) structAttrs(rv reflect.Value, depth int) []slog.Attr {
+	t := rv.Type()
+	attrs := make([]slog.Attr, 0, t.NumField())
+	for i := 0; i < t.NumField() && s.budget >= 0; i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		tag := f.Tag.Get("log")
+		if tag == "-" {
+			continue
+		}
+		name := f.Name
+		if j, _, _ := strings.Cut(f.Tag.Get("json"), ","); j != "" && j != "-" {
+			name = j
+		}
+		s.budget -= len(name) + 4
+
+		if tag == "redact" {
+			attrs = append(attrs, slog.Attr{Key: name, Value: s.str(redactedValue)})
+			continue
+		}
+		attrs = append(attrs, slog.Attr{Key: name, Value: s.value(rv.Field(i), depth+1)})
+	}
+
+	return attrs
+}
+
+// mapAttrs converts rv's entries, sorted by key for stable output.
+func (s *safeWalker) mapAttrs(rv reflect.Value, depth int) []slog.Attr {
+	// Each entry costs at least its separators, so a map too large to fit
+	// is given up on before its keys are listed and sorted
+	if rv.Len()*4 > s.budget {
+		s.budget = -1
+		return nil
+	}
+
+	keys := rv.MapKeys()
+	names := make([]string, len(keys))
+	for i, k := range keys {
+		names[i] = fmt.Sprint(k.Interface())
+	}
+	order := make([]int, len(keys))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool { return names[order[a]] < names[order[b]] })
+
+	attrs := make([]slog.Attr, 0, len(keys))
+	for _, i := range order {
+		if s.budget < 0 {
+			break
+		}
+		s.budget -= len(names[i]) + 4
+		attrs = append(attrs, slog.Attr{Key: names[i], Value: s.value(rv.MapIndex(keys[i]), depth+1)})
+	}
+
+	return attrs
+}
+
+// str charges s for a string value.
+func (s *safeWalker) str(v string) slog.Value {
+	s.budget -= len(v) + 2
+	return slog.StringValue(v)
+}
+
+// plain converts a group value to a map, for use inside slices where slog
+// has no group representation.
+func plain(v slog.Value) any {
+	if v.Kind() != slog.KindGroup {
+		return v.Any()
+	}
+
+	m := make(map[string]any, len(v.Group()))
+	for _, a := range v.Group() {
+		m[a.Key] = plain(a.Value)
+	}
+	return m
+}
+
+// safeTypeName names rv's type for the truncation fallback.
+func safeTypeName(rv reflect.Value) string {
+	if !rv.IsValid() {
+		return "nil"
+	}
+
+	return rv.Type().String()
+}
@@ -0,0 +1,103 @@
+package logger
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type safeDB struct {
+	Host     string `json:"host"`
+	Password string `log:"redact"`
+	Token    string `log:"-"`
+	internal string
+	Timeout  time.Duration
+}
+
+type safeNode struct {
+	Name string
+	Next *safeNode
+}
+
+// safeJSON logs Safe(v) and returns the rendered value.
+func safeJSON(t *testing.T, v any) any {
+	t.Helper()
+
+	out := &syncBuffer{}
+	New(WithWriter(out)).Info("m", "v", Safe(v))
+
+	return records(t, out.String())[0]["v"]
+}
+
+func TestSafeTags(t *testing.T) {
+	got, _ := safeJSON(t, &safeDB{Host: "db", Password: "hunter2", Token: "t", internal: "i", Timeout: time.Second}).(map[string]any)
+
+	want := map[string]any{"host": "db", "Password": redactedValue, "Timeout": float64(time.Second)}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s = %v, want %v", k, got[k], v)
+		}
+	}
+}
+
+func TestSafeValues(t *testing.T) {
+	got, _ := safeJSON(t, map[string]any{
+		"err":   errors.New("boom"),
+		"nil":   (*safeDB)(nil),
+		"list":  []safeDB{{Host: "a", Password: "p"}},
+		"bytes": []byte("raw"),
+		"n":     3,
+	}).(map[string]any)
+
+	if got["err"] != "boom" || got["nil"] != nil || got["bytes"] != "raw" || got["n"] != float64(3) {
+		t.Errorf("got %v", got)
+	}
+	list, _ := got["list"].([]any)
+	if len(list) != 1 || list[0].(map[string]any)["Password"] != redactedValue {
+		t.Errorf("list = %v, want its items redacted", got["list"])
+	}
+}
+
+func TestSafeDepth(t *testing.T) {
+	n := &safeNode{Name: "loop"}
+	n.Next = n
+
+	v := safeJSON(t, n)
+	for range SafeMaxDepth - 1 {
+		v = v.(map[string]any)["Next"]
+	}
+	if got, want := v.(map[string]any)["Next"], "logger.safeNode"+TruncatedSuffix; got != want {
+		t.Errorf("deepest value = %v, want %q", got, want)
+	}
+}
+
+func TestSafeTooLarge(t *testing.T) {
+	want := "logger.safeNode" + TruncatedSuffix
+	if got := safeJSON(t, safeNode{Name: strings.Repeat("x", SafeMaxSize)}); got != want {
+		t.Errorf("got %v, want %q", got, want)
+	}
+
+	if got := Safe(make([]struct{}, SafeMaxSize+1)).String(); got != "[]struct {}"+TruncatedSuffix {
+		t.Errorf("large slice = %q", got)
+	}
+}
+
+func TestSafeLargeMapIsCheap(t *testing.T) {
+	m := make(map[int]int, 1_000_000)
+	for i := range 1_000_000 {
+		m[i] = i
+	}
+
+	var v string
+	allocs := testing.AllocsPerRun(5, func() { v = Safe(m).String() })
+	if v != "map[int]int"+TruncatedSuffix {
+		t.Errorf("got %q", v)
+	}
+	if allocs > 10 {
+		t.Errorf("Safe allocated %.0f times for an oversized map", allocs)
+	}
+}
@@ -0,0 +1,144 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// defaultSamplingWindow is used when SamplingConfig.Window is not positive.
+const defaultSamplingWindow = time.Second
+
+// SamplingConfig controls how repetitive records are thinned out. Within each
+// Window, the first First records per key are logged, then one in every
+// Thereafter; the rest are dropped.
+type SamplingConfig struct {
+	// First is the number of records per key logged unconditionally each window.
+	First int
+	// Thereafter logs every Nth record after First. Zero drops them all.
+	Thereafter int
+	// Window is the period after which counters reset. Defaults to one second.
+	Window time.Duration
+	// Bypass is the level at and above which records are never sampled.
+	// Defaults to slog.LevelError.
+	Bypass slog.Leveler
+	// Key derives the sampling key from a record. Defaults to the message.
+	// The level is always part of the key.
+	Key func(ctx context.Context, r slog.Record) string
+}
+
+// WithSampling drops repetitive records according to cfg.
+func WithSampling(cfg SamplingConfig) Option {
+	return func(c *config) {
+		c.sampling = &cfg
+	}
+}
+
+// AttrSamplingKey returns a SamplingConfig.Key that samples by the string
+// value of the top-level attribute key instead of the message.
+func AttrSamplingKey(key string) func(context.Context, slog.Record) string {
+	return func(_ context.Context, r slog.Record) string {
+		var v string
+		r.Attrs(func(a slog.Attr) bool {
+			if a.Key == key {
+				v = a.Value.String()
+				return false
+			}
+			return true
+		})
+		return v
+	}
+}
+
+// samplingHandler drops records once their key exceeds the configured rate.
+type samplingHandler struct {
+	inner   slog.Handler
+	cfg     SamplingConfig
+	counter *sampleCounter
+}
+
+// sampleKey identifies a stream of similar records.
+type sampleKey struct {
+	level slog.Level
+	key   string
+}
+
+// sampleCounter is shared by handlers derived with WithAttrs or WithGroup so
+// the rate applies to the logger as a whole.
+type sampleCounter struct {
+	now func() time.Time
+
+	mu          sync.Mutex
+	windowStart time.Time
+	counts      map[sampleKey]int
+}
+
+// NewSamplingHandler wraps h with the sampling policy in cfg.
+func NewSamplingHandler(h slog.Handler, cfg SamplingConfig) slog.Handler {
+	return newSamplingHandler(h, cfg, time.Now)
+}
+
+// newSamplingHandler is NewSamplingHandler with the clock of WithClock.
+func newSamplingHandler(h slog.Handler, cfg SamplingConfig, now func() time.Time) slog.Handler {
+	if cfg.Window <= 0 {
+		cfg.Window = defaultSamplingWindow
+	}
+	if cfg.Bypass == nil {
+		cfg.Bypass = slog.LevelError
+	}
+
+	return &samplingHandler{
+		inner:   h,
+		cfg:     cfg,
+		counter: &sampleCounter{now: now, counts: make(map[sampleKey]int)},
+	}
+}
+
+func (h *samplingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+func (h *samplingHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level >= h.cfg.Bypass.Level() || h.allow(ctx, r) {
+		return h.inner.Handle(ctx, r)
+	}
+
+	return nil
+}
+
+func (h *samplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &samplingHandler{inner: h.inner.WithAttrs(attrs), cfg: h.cfg, counter: h.counter}
+}
+
+func (h *samplingHandler) WithGroup(name string) slog.Handler {
+	return &samplingHandler{inner: h.inner.WithGroup(name), cfg: h.cfg, counter: h.counter}
+}
+
+// allow counts r against its key and reports whether it should be logged.
+func (h *samplingHandler) allow(ctx context.Context, r slog.Record) bool {
+	key := sampleKey{level: r.Level, key: r.Message}
+	if h.cfg.Key != nil {
+		key.key = h.cfg.Key(ctx, r)
+	}
+
+	c := h.counter
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Reset all counters at window boundaries so the map stays bounded by the
+	// number of distinct keys seen in one window
+	now := c.now()
+	if now.Sub(c.windowStart) >= h.cfg.Window {
+		clear(c.counts)
+		c.windowStart = now
+	}
+
+	c.counts[key]++
+	n := c.counts[key]
+	if n <= h.cfg.First {
+		return true
+	}
+
+	return h.cfg.Thereafter > 0 && (n-h.cfg.First)%h.cfg.Thereafter == 0
+}
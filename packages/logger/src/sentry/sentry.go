@@ -0,0 +1,263 @@
+// Package sentry forwards error-level log records to Sentry. It lives in its
+// own package so the core logger does not depend on the Sentry SDK.
+package sentry
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+
+	logger "fuelers-go/packages/logger/src"
+)
+
+// defaultQueueSize is used when Options.QueueSize is not positive.
+const defaultQueueSize = 256
+
+// Options configures a Handler.
+type Options struct {
+	// Level is the minimum level captured. Defaults to slog.LevelError.
+	Level slog.Leveler
+	// Hub captures the events. Defaults to the hub on the record's context,
+	// then sentry.CurrentHub.
+	Hub *sentry.Hub
+	// QueueSize bounds the events waiting to be captured. When full, events
+	// are dropped rather than blocking the caller.
+	QueueSize int
+}
+
+// Handler writes every record to the wrapped handler and, for records at or
+// above the configured level, captures an event carrying the message, the
+// attributes as extras and the trace_id as a tag. Capturing happens on a
+// background goroutine, and nothing is sent while Sentry's client is not
+// initialized.
+type Handler struct {
+	inner  slog.Handler
+	opts   Options
+	attrs  []slog.Attr
+	prefix string
+	queue  *queue
+}
+
+// queue hands events to the background capture goroutine.
+type queue struct {
+	events chan event
+
+	mu     sync.RWMutex // guards closed against concurrent sends
+	closed bool
+	done   chan struct{}
+}
+
+// event is a captured record waiting to be sent.
+type event struct {
+	hub   *sentry.Hub
+	event *sentry.Event
+}
+
+// NewHandler wraps h and starts the capture goroutine.
+func NewHandler(h slog.Handler, opts Options) *Handler {
+	if opts.Level == nil {
+		opts.Level = slog.LevelError
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = defaultQueueSize
+	}
+
+	q := &queue{events: make(chan event, opts.QueueSize), done: make(chan struct{})}
+	go q.run()
+
+	return &Handler{inner: h, opts: opts, queue: q}
+}
+
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	err := h.inner.Handle(ctx, r)
+	if r.Level < h.opts.Level.Level() {
+		return err
+	}
+
+	hub := h.hub(ctx)
+	if hub == nil || hub.Client() == nil {
+		return err
+	}
+
+	h.queue.send(event{hub: hub, event: h.event(ctx, r)})
+
+	return err
+}
+
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := *h
+	h2.inner = h.inner.WithAttrs(attrs)
+	h2.attrs = append(h.attrs[:len(h.attrs):len(h.attrs)], prefixed(h.prefix, attrs)...)
+
+	return &h2
+}
+
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	h2 := *h
+	h2.inner = h.inner.WithGroup(name)
+	h2.prefix = h.prefix + name + "."
+
+	return &h2
+}
+
+// Close stops the capture goroutine after sending queued events and waits up
+// to timeout for Sentry to deliver them. Records logged afterwards are still
+// written to the wrapped handler but not captured. It is safe to call more
+// than once.
+func (h *Handler) Close(timeout time.Duration) {
+	q := h.queue
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.events)
+	}
+	q.mu.Unlock()
+
+	<-q.done
+	sentry.Flush(timeout)
+}
+
+// hub picks the hub for a record.
+func (h *Handler) hub(ctx context.Context) *sentry.Hub {
+	if h.opts.Hub != nil {
+		return h.opts.Hub
+	}
+	if ctx != nil {
+		if hub := sentry.GetHubFromContext(ctx); hub != nil {
+			return hub
+		}
+	}
+
+	return sentry.CurrentHub()
+}
+
+// event converts a record into a Sentry event.
+func (h *Handler) event(ctx context.Context, r slog.Record) *sentry.Event {
+	ev := sentry.NewEvent()
+	ev.Level = sentryLevel(r.Level)
+	ev.Message = r.Message
+	ev.Timestamp = r.Time
+	ev.Logger = "slog"
+
+	attrs := append([]slog.Attr(nil), h.attrs...)
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, prefixed(h.prefix, []slog.Attr{a})...)
+		return true
+	})
+
+	for _, a := range attrs {
+		if err, ok := attrError(a); ok {
+			if ev.Exception == nil {
+				ev.SetException(err, 10)
+			}
+			ev.Extra[a.Key] = err.Error()
+			continue
+		}
+
+		v := a.Value.Resolve()
+		if a.Key == "trace_id" || a.Key == "span_id" {
+			ev.Tags[a.Key] = v.String()
+			continue
+		}
+		ev.Extra[a.Key] = v.Any()
+	}
+
+	if id, ok := logger.TraceIDFromContext(ctx); ok {
+		ev.Tags["trace_id"] = id
+	}
+
+	return ev
+}
+
+// send enqueues e unless the queue is full or closed.
+func (q *queue) send(e event) {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	if q.closed {
+		return
+	}
+
+	select {
+	case q.events <- e:
+	default:
+		// Never block the caller on Sentry
+	}
+}
+
+// run captures events until the queue is closed.
+func (q *queue) run() {
+	defer close(q.done)
+
+	for e := range q.events {
+		e.hub.CaptureEvent(e.event)
+	}
+}
+
+// prefixed flattens groups and prefixes keys with the open group path.
+func prefixed(prefix string, attrs []slog.Attr) []slog.Attr {
+	out := make([]slog.Attr, 0, len(attrs))
+	for _, a := range attrs {
+		// Keep logger.Err values unresolved so the error can be recovered
+		if _, ok := attrError(a); !ok {
+			a.Value = a.Value.Resolve()
+		}
+		if a.Value.Kind() == slog.KindGroup {
+			p := prefix
+			if a.Key != "" {
+				p += a.Key + "."
+			}
+			out = append(out, prefixed(p, a.Value.Group())...)
+			continue
+		}
+		if a.Key == "" {
+			continue
+		}
+		a.Key = prefix + a.Key
+		out = append(out, a)
+	}
+
+	return out
+}
+
+// attrError returns the error carried by a, either directly or through the
+// Unwrap method of a logger.Err value.
+func attrError(a slog.Attr) (error, bool) {
+	switch v := a.Value.Any().(type) {
+	case error:
+		return v, true
+	case interface{ Unwrap() error }:
+		if a.Value.Kind() == slog.KindLogValuer {
+			return v.Unwrap(), true
+		}
+	}
+
+	return nil, false
+}
+
+// sentryLevel maps a slog level to the closest Sentry level.
+func sentryLevel(l slog.Level) sentry.Level {
+	switch {
+	case l >= slog.LevelError+4:
+		return sentry.LevelFatal
+	case l >= slog.LevelError:
+		return sentry.LevelError
+	case l >= slog.LevelWarn:
+		return sentry.LevelWarning
+	case l >= slog.LevelInfo:
+		return sentry.LevelInfo
+	default:
+		return sentry.LevelDebug
+	}
+}
@@ -0,0 +1,111 @@
+package sentry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+
+	logger "fuelers-go/packages/logger/src"
+)
+
+// transport records the events a client sends.
+type transport struct {
+	mu     sync.Mutex
+	events []*sentry.Event
+}
+
+func (t *transport) Flush(time.Duration) bool       { return true }
+func (t *transport) Configure(sentry.ClientOptions) {}
+func (t *transport) Close()                         {}
+
+func (t *transport) SendEvent(e *sentry.Event) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.events = append(t.events, e)
+}
+
+func (t *transport) sent() []*sentry.Event {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return append([]*sentry.Event(nil), t.events...)
+}
+
+func newTestHandler(t *testing.T) (*Handler, *transport) {
+	t.Helper()
+
+	tr := &transport{}
+	client, err := sentry.NewClient(sentry.ClientOptions{Transport: tr})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hub := sentry.NewHub(client, sentry.NewScope())
+
+	return NewHandler(slog.NewTextHandler(io.Discard, nil), Options{Hub: hub}), tr
+}
+
+func TestHandlerCapturesErrors(t *testing.T) {
+	h, tr := newTestHandler(t)
+	l := slog.New(h).With("component", "db")
+
+	ctx := logger.WithCorrelation(context.Background(), "abc")
+	l.InfoContext(ctx, "ignored")
+	l.WithGroup("req").ErrorContext(ctx, "query failed", logger.Err(errors.New("timeout")), "table", "users")
+	h.Close(time.Second)
+
+	events := tr.sent()
+	if len(events) != 1 {
+		t.Fatalf("captured %d events, want 1", len(events))
+	}
+	ev := events[0]
+	if ev.Message != "query failed" || ev.Level != sentry.LevelError {
+		t.Errorf("event = %q at %s", ev.Message, ev.Level)
+	}
+	if ev.Tags["trace_id"] != "abc" {
+		t.Errorf("trace_id tag = %q", ev.Tags["trace_id"])
+	}
+	if ev.Extra["component"] != "db" || ev.Extra["req.table"] != "users" {
+		t.Errorf("extras = %v", ev.Extra)
+	}
+	if len(ev.Exception) == 0 || ev.Exception[0].Value != "timeout" {
+		t.Errorf("exception = %+v", ev.Exception)
+	}
+}
+
+func TestHandlerAfterClose(t *testing.T) {
+	h, tr := newTestHandler(t)
+	h.Close(time.Second)
+	h.Close(time.Second)
+
+	// Must not send on the closed queue
+	slog.New(h).Error("late")
+
+	if n := len(tr.sent()); n != 0 {
+		t.Errorf("captured %d events after Close", n)
+	}
+}
+
+func TestHandlerConcurrentClose(t *testing.T) {
+	h, _ := newTestHandler(t)
+	l := slog.New(h)
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				l.Error("boom")
+			}
+		}()
+	}
+	h.Close(time.Second)
+	wg.Wait()
+}
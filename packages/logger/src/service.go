@@ -0,0 +1,76 @@
+package logger
+
+import (
+	"log/slog"
+	"os"
+	"sync"
+)
+
+// Keys of the attributes added by WithRuntimeInfo.
+const (
+	HostKey = "host"
+	PIDKey  = "pid"
+)
+
+// runtimeInfo resolves the host name and PID once per process. A failing
+// os.Hostname falls back to $HOSTNAME, which may be empty.
+var runtimeInfo = sync.OnceValue(func() []any {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = os.Getenv("HOSTNAME")
+	}
+
+	return []any{slog.String(HostKey, host), slog.Int(PIDKey, os.Getpid())}
+})
+
+// ServiceInfo identifies the service emitting the logs. Empty fields fall back
+// to SERVICE_NAME, SERVICE_VERSION and DEPLOY_ENV.
+type ServiceInfo struct {
+	Name        string
+	Version     string
+	Environment string
+}
+
+// Init attaches the service metadata to the default logger so it appears on
+// every subsequent record, including those produced via Ctx. Call it once at
+// startup, before the logger is shared across goroutines.
+func Init(info ServiceInfo) {
+	SetDefault(Default().With(info.attrs()...))
+}
+
+// WithServiceInfo attaches the service metadata to every record of a logger
+// created with New.
+func WithServiceInfo(info ServiceInfo) Option {
+	return func(c *config) {
+		c.attrs = append(c.attrs, info.attrs()...)
+	}
+}
+
+// WithRuntimeInfo attaches the host name and process ID to every record of a
+// logger created with New, to tell replicas apart.
+func WithRuntimeInfo() Option {
+	return func(c *config) {
+		c.attrs = append(c.attrs, runtimeInfo()...)
+	}
+}
+
+// attrs resolves the env fallbacks and returns the non-empty fields.
+func (s ServiceInfo) attrs() []any {
+	fields := []struct{ key, value, env string }{
+		{"service.name", s.Name, ServiceNameEnv},
+		{"service.version", s.Version, ServiceVersionEnv},
+		{"deployment.environment", s.Environment, DeployEnvEnv},
+	}
+
+	attrs := make([]any, 0, len(fields))
+	for _, f := range fields {
+		if f.value == "" {
+			f.value = os.Getenv(f.env)
+		}
+		if f.value != "" {
+			attrs = append(attrs, slog.String(f.key, f.value))
+		}
+	}
+
+	return attrs
+}
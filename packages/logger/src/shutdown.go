@@ -0,0 +1,40 @@
+package logger
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// RegisterShutdown is for programs that do not handle signals themselves: on
+// the first SIGINT or SIGTERM it calls Sync and exits through ExitFunc with
+// the conventional 128+signal status. Programs with their own graceful
+// shutdown should instead defer Sync in main. The returned function removes
+// the signal handler.
+func RegisterShutdown() (stop func()) {
+	sigs := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		select {
+		case sig := <-sigs:
+			_ = Sync()
+			code := 1
+			if s, ok := sig.(syscall.Signal); ok {
+				code = 128 + int(s)
+			}
+			ExitFunc(code)
+		case <-done:
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(sigs)
+			close(done)
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package logger
+
+import (
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+// WithSourceTrim strips prefix, such as the repository root, from source file
+// paths, so logs show packages/foo/bar.go instead of a build machine's
+// absolute path. The prefix matches whole path elements only, so "/repo"
+// leaves "/repository" alone. It only has an effect with WithAddSource.
+func WithSourceTrim(prefix string) Option {
+	return func(c *config) {
+		c.source.trim = prefix
+	}
+}
+
+// WithShortSource reports the source as a "file:line" string instead of an
+// object with function, file and line. FormatECS keeps its log.origin fields.
+func WithShortSource() Option {
+	return func(c *config) {
+		c.source.short = true
+	}
+}
+
+// sourceConfig customizes the built-in source attribute.
+type sourceConfig struct {
+	trim  string
+	short bool
+}
+
+// replacer returns the ReplaceAttr step for the source settings, or nil when
+// they are all defaults.
+func (s sourceConfig) replacer(format Format) func([]string, slog.Attr) slog.Attr {
+	short := s.short && format != FormatECS
+	if s.trim == "" && !short {
+		return nil
+	}
+
+	return func(groups []string, a slog.Attr) slog.Attr {
+		if len(groups) > 0 || a.Key != slog.SourceKey {
+			return a
+		}
+		src, ok := a.Value.Any().(*slog.Source)
+		if !ok {
+			return a
+		}
+
+		file := src.File
+		if s.trim != "" {
+			file = trimDir(file, s.trim)
+		}
+		if short {
+			return slog.String(a.Key, file+":"+strconv.Itoa(src.Line))
+		}
+
+		trimmed := *src
+		trimmed.File = file
+		return slog.Any(a.Key, &trimmed)
+	}
+}
+
+// trimDir strips the directory prefix from file if it ends at a path boundary.
+func trimDir(file, prefix string) string {
+	rest, ok := strings.CutPrefix(file, prefix)
+	if !ok {
+		return file
+	}
+	if strings.HasSuffix(prefix, "/") {
+		return rest
+	}
+	if after, ok := strings.CutPrefix(rest, "/"); ok {
+		return after
+	}
+
+	return file
+}
@@ -0,0 +1,34 @@
+package logger
+
+import (
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestTrimDir(t *testing.T) {
+	tests := []struct {
+		file, prefix, want string
+	}{
+		{"/repo/pkg/x.go", "/repo", "pkg/x.go"},
+		{"/repo/pkg/x.go", "/repo/", "pkg/x.go"},
+		{"/repository/x.go", "/repo", "/repository/x.go"},
+		{"/other/x.go", "/repo", "/other/x.go"},
+		{"/repo", "/repo", "/repo"},
+	}
+	for _, tt := range tests {
+		if got := trimDir(tt.file, tt.prefix); got != tt.want {
+			t.Errorf("trimDir(%q, %q) = %q, want %q", tt.file, tt.prefix, got, tt.want)
+		}
+	}
+}
+
+func TestSourceTrim(t *testing.T) {
+	_, file, _, _ := runtime.Caller(0)
+	out := &syncBuffer{}
+	New(WithWriter(out), WithAddSource(true), WithShortSource(), WithSourceTrim(filepath.Dir(file))).Info("hi")
+
+	if got, _ := records(t, out.String())[0]["source"].(string); filepath.Dir(got) != "." {
+		t.Errorf("source = %q, want a path relative to the package", got)
+	}
+}
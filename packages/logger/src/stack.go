@@ -0,0 +1,68 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StackDepth is the maximum number of frames captured by ErrorWithStack.
+var StackDepth = 32
+
+// StackTraceKey is the attribute holding a captured stack trace.
+const StackTraceKey = "stacktrace"
+
+// ErrorWithStack logs err at error level together with the stack of the
+// calling goroutine, starting at the caller. The stack is only captured when
+// error records are enabled.
+func ErrorWithStack(ctx context.Context, msg string, err error, args ...any) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	l := Ctx(ctx)
+	if !l.Enabled(ctx, slog.LevelError) {
+		return
+	}
+
+	pcs := make([]uintptr, max(StackDepth, 1))
+	n := runtime.Callers(2, pcs) // skip [Callers, ErrorWithStack]
+	pcs = pcs[:n]
+
+	var pc uintptr
+	if n > 0 {
+		pc = pcs[0]
+	}
+
+	r := slog.NewRecord(time.Now(), slog.LevelError, msg, pc)
+	r.AddAttrs(Err(err))
+	r.Add(args...)
+	r.AddAttrs(slog.String(StackTraceKey, formatStack(pcs)))
+	_ = l.Handler().Handle(ctx, r)
+}
+
+// formatStack renders program counters in the style of a Go panic trace:
+// the function on one line, then its tab-indented file:line.
+func formatStack(pcs []uintptr) string {
+	var b strings.Builder
+	frames := runtime.CallersFrames(pcs)
+	for {
+		f, more := frames.Next()
+		if f.Function != "" {
+			b.WriteString(f.Function)
+			b.WriteString("\n\t")
+			b.WriteString(f.File)
+			b.WriteByte(':')
+			b.WriteString(strconv.Itoa(f.Line))
+			b.WriteByte('\n')
+		}
+		if !more {
+			break
+		}
+	}
+
+	return b.String()
+}
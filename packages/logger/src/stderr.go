@@ -0,0 +1,37 @@
+package logger
+
+import (
+	"log/slog"
+	"os"
+)
+
+// stderrRouting describes how records are split between the configured writer
+// and os.Stderr.
+type stderrRouting struct {
+	threshold slog.Leveler
+	mirror    bool
+}
+
+// WithErrorToStderr sends error records to os.Stderr instead of the
+// configured writer, keeping the same format and attributes.
+func WithErrorToStderr() Option {
+	return WithStderrRouting(slog.LevelError, false)
+}
+
+// WithStderrRouting sends records at or above threshold to os.Stderr. When
+// mirror is true they are also written to the configured writer; otherwise
+// each record goes to exactly one stream.
+func WithStderrRouting(threshold slog.Leveler, mirror bool) Option {
+	return func(c *config) {
+		c.stderr = &stderrRouting{threshold: threshold, mirror: mirror}
+	}
+}
+
+// stderrRoute expresses the stderr settings as a level route.
+func (c *config) stderrRoute() LevelRoute {
+	return LevelRoute{
+		Min:      c.stderr.threshold,
+		Options:  []Option{WithWriter(os.Stderr)},
+		Continue: c.stderr.mirror,
+	}
+}
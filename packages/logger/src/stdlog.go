@@ -0,0 +1,46 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"log/slog"
+	"time"
+)
+
+// NewStdLogger returns a standard library logger whose output is logged
+// through the default logger at level, for libraries that only accept a
+// *log.Logger, such as http.Server.ErrorLog.
+func NewStdLogger(level slog.Level) *log.Logger {
+	return log.New(NewWriter(level), "", 0)
+}
+
+// NewWriter returns an io.Writer that logs each write as one message at level
+// through the default logger current at the time of the write. Trailing
+// newlines are trimmed and empty writes are ignored.
+func NewWriter(level slog.Level) io.Writer {
+	return &levelWriter{level: level}
+}
+
+// levelWriter adapts io.Writer to the default logger.
+type levelWriter struct {
+	level slog.Level
+}
+
+func (w *levelWriter) Write(p []byte) (int, error) {
+	msg := bytes.TrimRight(p, "\r\n")
+	if len(msg) == 0 {
+		return len(p), nil
+	}
+
+	ctx := context.Background()
+	h := Default().Handler()
+	if !h.Enabled(ctx, w.level) {
+		return len(p), nil
+	}
+
+	// There is no meaningful caller to report, so the record has no source
+	r := slog.NewRecord(time.Now(), w.level, string(msg), 0)
+	return len(p), h.Handle(ctx, r)
+}
@@ -0,0 +1,123 @@
+//go:build !windows && !plan9
+
+package logger
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"log/syslog"
+	"sync"
+)
+
+// SyslogOptions configures NewSyslogHandler.
+type SyslogOptions struct {
+	// Network and Addr select the daemon, as for syslog.Dial. Leave both
+	// empty to use the local syslog socket.
+	Network string
+	Addr    string
+	// Facility defaults to syslog.LOG_USER.
+	Facility syslog.Priority
+	// Level is the minimum level handled; Info when nil.
+	Level slog.Leveler
+	// AddSource adds the caller's file and line to the body.
+	AddSource bool
+}
+
+// SyslogHandler sends each record to a syslog daemon with a priority mapped
+// from its level: Error and above to LOG_ERR, Warn to LOG_WARNING, Info to
+// LOG_INFO and anything lower to LOG_DEBUG. The message body is the record
+// as a JSON object, without the time, which syslog stamps itself. If the
+// connection drops, the next write reconnects.
+type SyslogHandler struct {
+	sink *syslogSink
+	json slog.Handler
+}
+
+// syslogSink is shared by a handler and its WithAttrs/WithGroup children.
+// The JSON handler renders into buf, which is then sent under the same lock.
+type syslogSink struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+	w   *syslog.Writer
+}
+
+func (s *syslogSink) Write(p []byte) (int, error) {
+	return s.buf.Write(p)
+}
+
+// NewSyslogHandler connects to the syslog daemon and returns a handler that
+// logs under tag. A nil opts uses the local daemon at Info level.
+func NewSyslogHandler(tag string, opts *SyslogOptions) (*SyslogHandler, error) {
+	var o SyslogOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.Facility == 0 {
+		o.Facility = syslog.LOG_USER
+	}
+
+	// syslog.Writer redials and retries once when a write fails
+	w, err := syslog.Dial(o.Network, o.Addr, o.Facility|syslog.LOG_INFO, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	sink := &syslogSink{w: w}
+	json := slog.NewJSONHandler(sink, &slog.HandlerOptions{
+		Level:     o.Level,
+		AddSource: o.AddSource,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+
+			return replaceLevel(groups, a)
+		},
+	})
+
+	return &SyslogHandler{sink: sink, json: json}, nil
+}
+
+func (h *SyslogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.json.Enabled(ctx, level)
+}
+
+func (h *SyslogHandler) Handle(ctx context.Context, r slog.Record) error {
+	s := h.sink
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.buf.Reset()
+	if err := h.json.Handle(ctx, r); err != nil {
+		return err
+	}
+	msg := string(bytes.TrimSuffix(s.buf.Bytes(), []byte("\n")))
+
+	switch {
+	case r.Level >= slog.LevelError:
+		return s.w.Err(msg)
+	case r.Level >= slog.LevelWarn:
+		return s.w.Warning(msg)
+	case r.Level >= slog.LevelInfo:
+		return s.w.Info(msg)
+	default:
+		return s.w.Debug(msg)
+	}
+}
+
+func (h *SyslogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &SyslogHandler{sink: h.sink, json: h.json.WithAttrs(attrs)}
+}
+
+func (h *SyslogHandler) WithGroup(name string) slog.Handler {
+	return &SyslogHandler{sink: h.sink, json: h.json.WithGroup(name)}
+}
+
+// Close closes the connection to the daemon.
+func (h *SyslogHandler) Close() error {
+	h.sink.mu.Lock()
+	defer h.sink.mu.Unlock()
+
+	return h.sink.w.Close()
+}
@@ -0,0 +1,202 @@
+// Package testutil captures records emitted through the logger package so
+// tests can assert on them.
+package testutil
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	logger "fuelers-go/packages/logger/src"
+)
+
+// Entry is one decoded JSON record.
+type Entry struct {
+	Time    time.Time
+	Level   string
+	Message string
+	// Attrs holds every other top-level key, as decoded by encoding/json.
+	Attrs map[string]any
+}
+
+// Recorder is an in-memory log destination. It is safe for concurrent use.
+type Recorder struct {
+	// Logger writes JSON records into the recorder.
+	Logger *slog.Logger
+
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+// NewRecorder returns a recorder whose Logger emits JSON at debug level and
+// above. Additional options are applied after those defaults.
+func NewRecorder(opts ...logger.Option) *Recorder {
+	r := &Recorder{}
+	base := []logger.Option{
+		logger.WithWriter(r),
+		logger.WithFormat(logger.FormatJSON),
+		logger.WithLevel(slog.LevelDebug),
+	}
+	r.Logger = logger.New(append(base, opts...)...)
+
+	return r
+}
+
+// Capture installs a recorder as the package's default logger for the
+// duration of the test and restores the previous logger on cleanup.
+func Capture(t testing.TB, opts ...logger.Option) *Recorder {
+	t.Helper()
+
+	r := NewRecorder(opts...)
+	prev := logger.Default()
+	logger.SetDefault(r.Logger)
+	t.Cleanup(func() {
+		logger.SetDefault(prev)
+	})
+
+	return r
+}
+
+// Write implements io.Writer.
+func (r *Recorder) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.buf.Write(p)
+}
+
+// String returns the raw captured output.
+func (r *Recorder) String() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.buf.String()
+}
+
+// Reset discards everything captured so far.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.buf.Reset()
+}
+
+// Entries decodes the captured output. Lines that are not JSON objects are
+// skipped.
+func (r *Recorder) Entries() []Entry {
+	return r.entries(false)
+}
+
+// entries decodes the output, with numbers as json.Number when exact is set
+// so that 64-bit IDs compare without float64 rounding.
+func (r *Recorder) entries(exact bool) []Entry {
+	var entries []Entry
+
+	sc := bufio.NewScanner(bytes.NewReader([]byte(r.String())))
+	sc.Buffer(nil, 1<<20)
+	for sc.Scan() {
+		var raw map[string]any
+		if err := unmarshal(sc.Bytes(), &raw, exact); err != nil {
+			continue
+		}
+		entries = append(entries, decode(raw))
+	}
+
+	return entries
+}
+
+// Find returns the first entry at level with message msg whose attributes
+// contain every key-value pair in args.
+func (r *Recorder) Find(level slog.Level, msg string, args ...any) (Entry, bool) {
+	want := expected(args)
+	for _, e := range r.entries(true) {
+		if e.Level == logger.LevelName(level) && e.Message == msg && e.matches(want) {
+			return e, true
+		}
+	}
+
+	return Entry{}, false
+}
+
+// AssertLogged fails the test unless a matching entry was recorded.
+func (r *Recorder) AssertLogged(t testing.TB, level slog.Level, msg string, args ...any) {
+	t.Helper()
+
+	if _, ok := r.Find(level, msg, args...); !ok {
+		t.Errorf("no %s record %q with %v; captured:\n%s", level, msg, args, r.String())
+	}
+}
+
+// AssertNotLogged fails the test if a matching entry was recorded.
+func (r *Recorder) AssertNotLogged(t testing.TB, level slog.Level, msg string, args ...any) {
+	t.Helper()
+
+	if _, ok := r.Find(level, msg, args...); ok {
+		t.Errorf("unexpected %s record %q with %v", level, msg, args)
+	}
+}
+
+// decode splits the built-in keys out of a raw record.
+func decode(raw map[string]any) Entry {
+	e := Entry{Attrs: raw}
+	if v, ok := raw[slog.TimeKey].(string); ok {
+		e.Time, _ = time.Parse(time.RFC3339Nano, v)
+	}
+	e.Level, _ = raw[slog.LevelKey].(string)
+	e.Message, _ = raw[slog.MessageKey].(string)
+	delete(raw, slog.TimeKey)
+	delete(raw, slog.LevelKey)
+	delete(raw, slog.MessageKey)
+
+	return e
+}
+
+// expected normalizes key-value pairs through a JSON round trip so they
+// compare equal to attributes decoded with exact numbers.
+func expected(args []any) map[string]any {
+	var rec slog.Record
+	rec.Add(args...)
+
+	want := make(map[string]any, rec.NumAttrs())
+	rec.Attrs(func(a slog.Attr) bool {
+		var v any
+		b, err := json.Marshal(a.Value.Resolve().Any())
+		if err == nil && unmarshal(b, &v, true) == nil {
+			want[a.Key] = v
+		} else {
+			want[a.Key] = fmt.Sprint(a.Value)
+		}
+		return true
+	})
+
+	return want
+}
+
+// matches reports whether e carries every expected attribute.
+func (e Entry) matches(want map[string]any) bool {
+	for k, v := range want {
+		got, ok := e.Attrs[k]
+		if !ok || !reflect.DeepEqual(got, v) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// unmarshal decodes JSON into v, keeping numbers as json.Number when exact.
+func unmarshal(data []byte, v any, exact bool) error {
+	if !exact {
+		return json.Unmarshal(data, v)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
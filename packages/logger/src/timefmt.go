@@ -0,0 +1,57 @@
+package logger
+
+import "log/slog"
+
+// timeConfig customizes the built-in time attribute.
+type timeConfig struct {
+	key    string
+	layout string
+	omit   bool
+}
+
+// WithTimeKey renames the built-in time attribute, e.g. to "timestamp".
+func WithTimeKey(key string) Option {
+	return func(c *config) {
+		c.time.key = key
+	}
+}
+
+// WithTimeFormat renders the built-in time attribute as a string using the
+// time.Format layout, e.g. time.RFC3339Nano.
+func WithTimeFormat(layout string) Option {
+	return func(c *config) {
+		c.time.layout = layout
+	}
+}
+
+// WithoutTime drops the built-in time attribute, which keeps golden-file
+// comparisons stable in tests.
+func WithoutTime() Option {
+	return func(c *config) {
+		c.time.omit = true
+	}
+}
+
+// replacer returns the ReplaceAttr step for the time settings, or nil when
+// they are all defaults.
+func (t timeConfig) replacer() func([]string, slog.Attr) slog.Attr {
+	if t == (timeConfig{}) {
+		return nil
+	}
+
+	return func(groups []string, a slog.Attr) slog.Attr {
+		if len(groups) > 0 || a.Key != slog.TimeKey || a.Value.Kind() != slog.KindTime {
+			return a
+		}
+		if t.omit {
+			return slog.Attr{}
+		}
+		if t.layout != "" {
+			a.Value = slog.StringValue(a.Value.Time().Format(t.layout))
+		}
+		if t.key != "" {
+			a.Key = t.key
+		}
+		return a
+	}
+}
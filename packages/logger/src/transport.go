@@ -0,0 +1,121 @@
+package logger
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// LoggingTransport is an http.RoundTripper that logs one line per outbound
+// request with its method, URL, status and duration, and forwards the
+// context's trace ID in the X-Trace-Id header. Failed requests and 5xx
+// responses are logged at error level. Set its fields before first use.
+type LoggingTransport struct {
+	// Base performs the requests; http.DefaultTransport when nil.
+	Base http.RoundTripper
+	// AllowQuery lists the query parameters logged as is. The values of all
+	// others are logged as "[REDACTED]", as is any password in the URL.
+	AllowQuery []string
+	// MaxBodyBytes, when positive, logs up to that many bytes of the request
+	// and response bodies. Bodies are not logged by default.
+	MaxBodyBytes int
+}
+
+// NewLoggingTransport wraps base, or http.DefaultTransport when nil.
+func NewLoggingTransport(base http.RoundTripper) *LoggingTransport {
+	return &LoggingTransport{Base: base}
+}
+
+func (t *LoggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	// A RoundTripper must not modify the caller's request
+	out := req
+	id, _ := TraceIDFromContext(ctx)
+	setID := id != "" && req.Header.Get(TraceIDHeader) == ""
+	logBody := t.MaxBodyBytes > 0 && req.Body != nil && req.Body != http.NoBody
+	if setID || logBody {
+		out = req.Clone(ctx)
+	}
+	if setID {
+		out.Header.Set(TraceIDHeader, id)
+	}
+
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("url", t.redactURL(req.URL)),
+	}
+	if logBody {
+		var body []byte
+		body, out.Body = peekBody(out.Body, t.MaxBodyBytes)
+		attrs = append(attrs, slog.String("request_body", string(body)))
+	}
+
+	start := time.Now()
+	resp, err := base.RoundTrip(out)
+	attrs = append(attrs, Since("duration", start))
+
+	level := slog.LevelInfo
+	switch {
+	case err != nil:
+		level = slog.LevelError
+		attrs = append(attrs, Err(err))
+	default:
+		if resp.StatusCode >= http.StatusInternalServerError {
+			level = slog.LevelError
+		}
+		attrs = append(attrs, slog.Int("status", resp.StatusCode))
+		if t.MaxBodyBytes > 0 && resp.Body != nil && resp.Body != http.NoBody {
+			var body []byte
+			body, resp.Body = peekBody(resp.Body, t.MaxBodyBytes)
+			attrs = append(attrs, slog.String("response_body", string(body)))
+		}
+	}
+
+	Ctx(ctx).LogAttrs(ctx, level, "http client request", attrs...)
+
+	return resp, err
+}
+
+// redactURL renders u with the values of non-allowed query parameters and
+// any password masked, keeping the parameter order.
+func (t *LoggingTransport) redactURL(u *url.URL) string {
+	r := *u
+	if r.RawQuery != "" {
+		pairs := strings.Split(r.RawQuery, "&")
+		for i, p := range pairs {
+			key, _, hasValue := strings.Cut(p, "=")
+			if k, err := url.QueryUnescape(key); err == nil {
+				key = k
+			}
+			if hasValue && !slices.Contains(t.AllowQuery, key) {
+				pairs[i] = url.QueryEscape(key) + "=" + redactedValue
+			}
+		}
+		r.RawQuery = strings.Join(pairs, "&")
+	}
+
+	return r.Redacted()
+}
+
+// peekBody reads up to n bytes of body and returns them with a body that
+// still yields the entire content.
+func peekBody(body io.ReadCloser, n int) ([]byte, io.ReadCloser) {
+	buf := make([]byte, n)
+	read, _ := io.ReadFull(body, buf)
+	buf = buf[:read]
+
+	return buf, struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(buf), body), body}
+}
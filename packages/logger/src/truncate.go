@@ -0,0 +1,62 @@
+package logger
+
+import (
+	"log/slog"
+	"unicode/utf8"
+)
+
+// TruncatedSuffix is appended to values shortened by WithMaxValueLen.
+const TruncatedSuffix = "...(truncated)"
+
+// WithMaxValueLen shortens string and []byte attribute values longer than n
+// characters to their first n characters followed by TruncatedSuffix, at any
+// group depth. Other kinds are left alone, as are the top-level time, level,
+// source and message, which the other options may have turned into strings.
+func WithMaxValueLen(n int) Option {
+	return func(c *config) {
+		if n <= 0 {
+			return
+		}
+		c.replacers = append(c.replacers, func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 {
+				switch a.Key {
+				case slog.TimeKey, slog.LevelKey, slog.SourceKey, slog.MessageKey, c.time.key:
+					return a
+				}
+			}
+
+			switch a.Value.Kind() {
+			case slog.KindString:
+				if s, ok := truncate(a.Value.String(), n); ok {
+					a.Value = slog.StringValue(s)
+				}
+			case slog.KindAny:
+				if b, isBytes := a.Value.Any().([]byte); isBytes {
+					if s, ok := truncate(string(b), n); ok {
+						a.Value = slog.StringValue(s)
+					}
+				}
+			}
+			return a
+		})
+	}
+}
+
+// truncate cuts s after n runes, reporting whether it did.
+func truncate(s string, n int) (string, bool) {
+	if len(s) <= n {
+		return s, false
+	}
+
+	i, count := 0, 0
+	for i < len(s) && count < n {
+		_, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		count++
+	}
+	if i >= len(s) {
+		return s, false
+	}
+
+	return s[:i] + TruncatedSuffix, true
+}
@@ -0,0 +1,46 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMaxValueLen(t *testing.T) {
+	out := &syncBuffer{}
+	New(WithWriter(out), WithMaxValueLen(3)).Info("long message", "s", "abcdef", "b", []byte("ghijkl"), slog.Group("g", "s", "mnopqr"), "n", 123456)
+
+	r := records(t, out.String())[0]
+	want := map[string]any{
+		"msg": "long message",
+		"s":   "abc" + TruncatedSuffix,
+		"b":   "ghi" + TruncatedSuffix,
+		"n":   float64(123456),
+	}
+	for k, v := range want {
+		if r[k] != v {
+			t.Errorf("%s = %v, want %v", k, r[k], v)
+		}
+	}
+	if g := r["g"].(map[string]any); g["s"] != "mno"+TruncatedSuffix {
+		t.Errorf("g.s = %v", g["s"])
+	}
+}
+
+func TestMaxValueLenKeepsBuiltins(t *testing.T) {
+	out := &syncBuffer{}
+	New(WithWriter(out), WithMaxValueLen(3), WithLevel(LevelTrace), WithAddSource(true), WithShortSource(),
+		WithTimeFormat(time.RFC3339), WithTimeKey("ts")).Log(context.Background(), LevelTrace, "hi")
+
+	r := records(t, out.String())[0]
+	for _, key := range []string{"ts", "level", "source"} {
+		if v, _ := r[key].(string); strings.HasSuffix(v, TruncatedSuffix) {
+			t.Errorf("built-in %s truncated: %q", key, v)
+		}
+	}
+	if r["level"] != "TRACE" {
+		t.Errorf("level = %v", r["level"])
+	}
+}